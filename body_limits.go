@@ -0,0 +1,144 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Entry types for requests rejected before their body is decoded.
+const (
+	errTypePayloadTooLarge      = "payload_too_large"
+	errTypeUnsupportedMediaType = "unsupported_media_type"
+)
+
+// bodyRejectedError is returned by parseInput when the request body violates
+// OpenAPIOptions.MaxBodySize (413) or OpenAPIOptions.Consumes (415).
+type bodyRejectedError struct {
+	Status     int
+	Type       string
+	Loc        []any
+	Field      string
+	Msg        string
+	Constraint string
+}
+
+func (e *bodyRejectedError) Error() string { return e.Msg }
+
+func newPayloadTooLargeError(size, limit int64) *bodyRejectedError {
+	return &bodyRejectedError{
+		Status:     fiber.StatusRequestEntityTooLarge,
+		Type:       errTypePayloadTooLarge,
+		Loc:        []any{"body"},
+		Msg:        fmt.Sprintf("request body is %d bytes; the limit is %d bytes", size, limit),
+		Constraint: fmt.Sprintf("max_body_size=%d", limit),
+	}
+}
+
+func newUnsupportedMediaTypeError(contentType string, accepted []string) *bodyRejectedError {
+	msg := fmt.Sprintf("Content-Type %q is not accepted; expected one of: %s", contentType, strings.Join(accepted, ", "))
+	if contentType == "" {
+		msg = fmt.Sprintf("missing Content-Type; expected one of: %s", strings.Join(accepted, ", "))
+	}
+	return &bodyRejectedError{
+		Status:     fiber.StatusUnsupportedMediaType,
+		Type:       errTypeUnsupportedMediaType,
+		Loc:        []any{"header", "Content-Type"},
+		Field:      "Content-Type",
+		Msg:        msg,
+		Constraint: "consumes=" + strings.Join(accepted, " "),
+	}
+}
+
+// checkRequestBody enforces the per-operation body size limit and accepted
+// media types. Requests without a body always pass: required-ness is a
+// validation concern.
+func checkRequestBody(c fiber.Ctx, options *OpenAPIOptions) error {
+	if options == nil {
+		return nil
+	}
+	size := int64(len(c.Body()))
+	if size == 0 {
+		return nil
+	}
+	if options.MaxBodySize > 0 && size > options.MaxBodySize {
+		return newPayloadTooLargeError(size, options.MaxBodySize)
+	}
+	if len(options.Consumes) > 0 {
+		contentType := c.Get(fiber.HeaderContentType)
+		if !mediaTypeAccepted(contentType, options.Consumes) {
+			return newUnsupportedMediaTypeError(contentType, options.Consumes)
+		}
+	}
+	return nil
+}
+
+// mediaTypeAccepted reports whether the Content-Type header value matches one
+// of the accepted media types. Parameters such as charset are ignored and
+// "type/*" entries match any subtype.
+func mediaTypeAccepted(contentType string, accepted []string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range accepted {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == mediaType || a == "*/*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(a, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// requestMediaTypes returns the media types documented for an operation's
+// request body.
+func requestMediaTypes(options OpenAPIOptions) []string {
+	if len(options.Consumes) > 0 {
+		return options.Consumes
+	}
+	return []string{"application/json"}
+}
+
+func examplePayloadTooLargeEnvelope(limit int64) func() ErrorEnvelope {
+	return func() ErrorEnvelope {
+		return exampleBodyRejectedEnvelope(newPayloadTooLargeError(limit+1, limit))
+	}
+}
+
+func exampleUnsupportedMediaTypeEnvelope(accepted []string) func() ErrorEnvelope {
+	return func() ErrorEnvelope {
+		return exampleBodyRejectedEnvelope(newUnsupportedMediaTypeError("text/plain", accepted))
+	}
+}
+
+func exampleBodyRejectedEnvelope(e *bodyRejectedError) ErrorEnvelope {
+	return exampleEnvelope(e.entry())
+}
+
+func (e *bodyRejectedError) entry() ValidationErrorEntry {
+	return ValidationErrorEntry{
+		Type:       e.Type,
+		Code:       e.Status,
+		Loc:        e.Loc,
+		Field:      e.Field,
+		Msg:        e.Msg,
+		Constraint: e.Constraint,
+	}
+}
+
+func (e *bodyRejectedError) category() errorCategory {
+	return errorCategory{
+		Code:    e.Status,
+		Type:    e.Type,
+		Message: e.Msg,
+		Details: e.Constraint,
+	}
+}
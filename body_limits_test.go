@@ -0,0 +1,136 @@
+package fiberoapi
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadInput struct {
+	Name string `json:"name" form:"name"`
+}
+
+type uploadOutput struct {
+	OK bool `json:"ok"`
+}
+
+func createUpload(c fiber.Ctx, in uploadInput) (uploadOutput, error) {
+	return uploadOutput{OK: true}, nil
+}
+
+func TestBodyLimits_MaxBodySize(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{MaxBodySize: 16})
+
+	resp := sendRequest(t, oapi, "POST", "/uploads", `{"name":"a"}`)
+	assert.Equal(t, 200, resp.StatusCode, "%s", resp.body)
+
+	resp = sendRequest(t, oapi, "POST", "/uploads", `{"name":"a much longer name"}`)
+	require.Equal(t, 413, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	e := env.Errors[0]
+	assert.Equal(t, "payload_too_large", e.Type)
+	assert.Equal(t, 413, e.Code)
+	assert.Equal(t, []any{"body"}, e.Loc)
+	assert.Equal(t, "max_body_size=16", e.Constraint)
+	assert.Equal(t, "request body is 29 bytes; the limit is 16 bytes", e.Msg)
+}
+
+func TestBodyLimits_Consumes(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{
+		Consumes: []string{"application/json", "application/x-www-form-urlencoded"},
+	})
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", "application/json", `{"name":"a"}`, 200},
+		{"json with charset", "application/json; charset=utf-8", `{"name":"a"}`, 200},
+		{"upper case", "Application/JSON", `{"name":"a"}`, 200},
+		{"form", "application/x-www-form-urlencoded", `name=a`, 200},
+		{"not accepted", "text/plain", `name=a`, 415},
+		{"missing", "", `{"name":"a"}`, 415},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sendRequest(t, oapi, "POST", "/uploads", tc.body, "Content-Type", tc.contentType)
+			require.Equal(t, tc.status, resp.StatusCode, "%s", resp.body)
+			if tc.status != 415 {
+				return
+			}
+			env := resp.envelope(t)
+			require.Len(t, env.Errors, 1)
+			e := env.Errors[0]
+			assert.Equal(t, "unsupported_media_type", e.Type)
+			assert.Equal(t, []any{"header", "Content-Type"}, e.Loc)
+			assert.Equal(t, "Content-Type", e.Field)
+			assert.Equal(t, "consumes=application/json application/x-www-form-urlencoded", e.Constraint)
+		})
+	}
+}
+
+func TestBodyLimits_EmptyBodySkipsChecks(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{Consumes: []string{"application/json"}, MaxBodySize: 1})
+	resp := sendRequest(t, oapi, "POST", "/uploads", "")
+	assert.Equal(t, 200, resp.StatusCode, "%s", resp.body)
+}
+
+func TestMediaTypeAccepted(t *testing.T) {
+	assert.True(t, mediaTypeAccepted("image/png", []string{"image/*"}))
+	assert.True(t, mediaTypeAccepted("text/csv", []string{"*/*"}))
+	assert.False(t, mediaTypeAccepted("imagex/png", []string{"image/*"}))
+	assert.False(t, mediaTypeAccepted("not a media type", []string{"application/json"}))
+}
+
+func TestBodyLimits_DefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{DefaultErrorShape: &UniErr{}})
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{Consumes: []string{"application/json"}})
+	resp := sendRequest(t, oapi, "POST", "/uploads", "x", "Content-Type", "text/plain")
+	require.Equal(t, 415, resp.StatusCode)
+
+	var body UniErr
+	resp.decode(t, &body)
+	assert.Equal(t, "unsupported_media_type", body.Type)
+	assert.Equal(t, `Content-Type "text/plain" is not accepted; expected one of: application/json`, body.Message)
+	assert.Equal(t, "consumes=application/json", body.Details)
+}
+
+func TestBodyLimits_Spec(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{
+		Consumes:    []string{"application/json", "multipart/form-data"},
+		MaxBodySize: 1 << 20,
+	})
+	op := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/uploads"].(map[string]interface{})["post"].(map[string]interface{})
+
+	requestBody := op["requestBody"].(map[string]interface{})
+	assert.Equal(t, int64(1<<20), requestBody["x-max-body-size"])
+	content := requestBody["content"].(map[string]interface{})
+	assert.Contains(t, content, "application/json")
+	assert.Contains(t, content, "multipart/form-data")
+
+	responses := op["responses"].(map[string]interface{})
+	assert.Contains(t, responses, "413")
+	assert.Contains(t, responses, "415")
+}
+
+func TestBodyLimits_SpecDefaults(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/uploads", createUpload, OpenAPIOptions{})
+	op := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/uploads"].(map[string]interface{})["post"].(map[string]interface{})
+
+	requestBody := op["requestBody"].(map[string]interface{})
+	assert.NotContains(t, requestBody, "x-max-body-size")
+	assert.Equal(t, []string{"application/json"}, sortedKeys(requestBody["content"].(map[string]interface{})))
+	responses := op["responses"].(map[string]interface{})
+	assert.NotContains(t, responses, "413")
+	assert.NotContains(t, responses, "415")
+}
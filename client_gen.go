@@ -0,0 +1,691 @@
+package fiberoapi
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoClientConfig controls the output of GenerateGoClient.
+type GoClientConfig struct {
+	PackageName string // Package clause of the generated file (default: "client")
+	ClientName  string // Name of the generated client struct (default: "Client")
+}
+
+// GenerateGoClient emits the source of a typed Go client for every visible
+// registered operation. Each operation becomes one method named after its
+// operationId (or method + path when none is set). Inputs are marshalled using
+// the same uri / query / header / json tags parseInput binds from, and error
+// responses are decoded into the declared OpenAPIOptions.Errors type matching
+// the status code.
+//
+// Every type reachable from the operations is re-declared in the generated
+// file, so the client does not import the server package. Hidden operations are
+// skipped, mirroring GenerateOpenAPISpec. Two types sharing a Go name, or a
+// type named like the client runtime (Client, ClientOption, APIError...), are
+// an error rather than a silently merged declaration.
+func (o *OApiApp) GenerateGoClient(cfg GoClientConfig) ([]byte, error) {
+	if scope := o.specScope(); scope != o {
+		return scope.GenerateGoClient(cfg)
+	}
+	if cfg.PackageName == "" {
+		cfg.PackageName = "client"
+	}
+	if cfg.ClientName == "" {
+		cfg.ClientName = "Client"
+	}
+
+	g := &goClientGen{
+		cfg:     cfg,
+		types:   map[string]reflect.Type{},
+		runtime: goClientRuntimeNames(cfg.ClientName),
+		imports: map[string]bool{"context": true, "encoding/json": true, "net/http": true, "net/url": true, "bytes": true, "fmt": true, "io": true},
+	}
+
+	var methods bytes.Buffer
+	seen := map[string]int{}
+	for _, op := range o.operations {
+		if op.Options.Hidden {
+			continue
+		}
+		name := goMethodName(op)
+		// Disambiguate duplicate operationIds deterministically rather than
+		// emitting a file that does not compile.
+		if n := seen[name]; n > 0 {
+			seen[name] = n + 1
+			name = name + strconv.Itoa(n+1)
+		} else {
+			seen[name] = 1
+		}
+		if err := g.writeMethod(&methods, name, op, o.libraryErrorShape()); err != nil {
+			return nil, err
+		}
+	}
+	if g.err != nil {
+		return nil, g.err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by fiberoapi-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", cfg.PackageName)
+
+	var typeDecls bytes.Buffer
+	g.writeTypes(&typeDecls)
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+	out.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&out, "\t%q\n", imp)
+	}
+	out.WriteString(")\n\n")
+
+	fmt.Fprintf(&out, goClientRuntime, cfg.ClientName, o.config.OpenAPITitle)
+	out.Write(typeDecls.Bytes())
+	out.Write(methods.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated client does not parse: %w", err)
+	}
+	return src, nil
+}
+
+// goClientRuntime is the fixed part of every generated client: construction,
+// request plumbing and the APIError type. The two verbs are the client struct
+// name and the API title.
+const goClientRuntime = `// %[1]s is a typed HTTP client for the %[2]s API.
+type %[1]s struct {
+	BaseURL        string
+	HTTPClient     *http.Client
+	RequestEditors []func(ctx context.Context, req *http.Request) error
+}
+
+// ClientOption customises a client built with New%[1]s.
+type ClientOption func(*%[1]s)
+
+// WithHTTPClient replaces the default http.Client.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *%[1]s) { c.HTTPClient = hc }
+}
+
+// WithRequestEditor registers a hook run on every outgoing request, e.g. to
+// inject an Authorization header.
+func WithRequestEditor(fn func(ctx context.Context, req *http.Request) error) ClientOption {
+	return func(c *%[1]s) { c.RequestEditors = append(c.RequestEditors, fn) }
+}
+
+// New%[1]s returns a client targeting baseURL (scheme and host, optionally a
+// path prefix).
+func New%[1]s(baseURL string, opts ...ClientOption) *%[1]s {
+	c := &%[1]s{BaseURL: baseURL, HTTPClient: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is returned for every non-2xx response. Value holds the decoded
+// body: a pointer to the declared error type for the status code, or nil when
+// the body did not match any known shape.
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	Value      any
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("unexpected status %%d: %%s", e.StatusCode, bytes.TrimSpace(e.Body))
+}
+
+func decodeAs[T any](body []byte) any {
+	v := new(T)
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func (c *%[1]s) do(ctx context.Context, method, path string, query url.Values, header http.Header, body any, out any, decodeErr func(status int, body []byte) any) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for _, edit := range c.RequestEditors {
+		if err := edit(ctx, req); err != nil {
+			return err
+		}
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &APIError{StatusCode: resp.StatusCode, Body: raw, Value: decodeErr(resp.StatusCode, raw)}
+	}
+	if out == nil || len(raw) == 0 {
+		return nil
+	}
+	return json.Unmarshal(raw, out)
+}
+
+`
+
+// goClientRuntimeNames lists the identifiers goClientRuntime declares, which
+// no re-declared type may take.
+func goClientRuntimeNames(clientName string) map[string]bool {
+	return map[string]bool{
+		clientName: true, "New" + clientName: true,
+		"ClientOption": true, "WithHTTPClient": true, "WithRequestEditor": true, "APIError": true,
+	}
+}
+
+// goClientGen accumulates the type declarations and imports required by the
+// generated methods.
+type goClientGen struct {
+	cfg     GoClientConfig
+	types   map[string]reflect.Type // declared name → type
+	runtime map[string]bool         // names taken by goClientRuntime
+	imports map[string]bool
+	err     error // first name collision
+}
+
+// declare registers t under name. It reports whether t still needs its
+// nested types registered, and records an error when name is already taken
+// by another type or by the client runtime.
+func (g *goClientGen) declare(name string, t reflect.Type) bool {
+	if existing, ok := g.types[name]; ok {
+		if existing != t && g.err == nil {
+			g.err = fmt.Errorf("generated client: type name %s is used by both %s and %s; rename one of them",
+				name, goTypeOrigin(existing), goTypeOrigin(t))
+		}
+		return false
+	}
+	if g.runtime[name] && g.err == nil {
+		g.err = fmt.Errorf("generated client: type %s clashes with the client's own %s; rename it or set GoClientConfig.ClientName",
+			goTypeOrigin(t), name)
+	}
+	g.types[name] = t
+	return true
+}
+
+// goTypeOrigin names t for collision errors: its package path and name, or
+// "anonymous struct" for an inline type.
+func goTypeOrigin(t reflect.Type) string {
+	if t.Name() == "" {
+		return "an anonymous " + t.Kind().String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// typeExpr renders t as a Go type expression, registering every named type it
+// reaches so writeTypes can declare it.
+func (g *goClientGen) typeExpr(t reflect.Type) string {
+	if t == nil {
+		return "any"
+	}
+	if t.Kind() == reflect.Ptr {
+		return "*" + g.typeExpr(t.Elem())
+	}
+	if isTimeType(t) {
+		g.imports["time"] = true
+		return "time.Time"
+	}
+	if t.Name() != "" && t.PkgPath() != "" {
+		// Types with custom JSON encoding cannot be re-declared faithfully;
+		// fall back to their wire representation.
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			return "json.RawMessage"
+		}
+		if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+			return "string"
+		}
+		name := goExportedName(t.Name())
+		if g.declare(name, t) {
+			// Register nested types eagerly so declarations are complete.
+			g.declBody(t)
+		}
+		return name
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "[]" + g.typeExpr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeExpr(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", g.typeExpr(t.Key()), g.typeExpr(t.Elem()))
+	case reflect.Interface:
+		return "any"
+	case reflect.Struct:
+		return g.structExpr(t)
+	default:
+		return t.Kind().String()
+	}
+}
+
+// declBody renders the underlying type of a named type.
+func (g *goClientGen) declBody(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.structExpr(t)
+	case reflect.Slice:
+		return "[]" + g.typeExpr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeExpr(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", g.typeExpr(t.Key()), g.typeExpr(t.Elem()))
+	case reflect.Interface:
+		return "any"
+	default:
+		return t.Kind().String()
+	}
+}
+
+// structExpr renders a struct literal type keeping only the tags the server
+// binds from. Parameter fields without an explicit json tag get json:"-" so
+// they are not duplicated into the request body.
+func (g *goClientGen) structExpr(t reflect.Type) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("openapi") == "-" {
+			continue
+		}
+		var tags []string
+		jsonTag, hasJSON := field.Tag.Lookup("json")
+		isParam := false
+		for _, key := range []string{"uri", "query", "header"} {
+			if v := field.Tag.Get(key); v != "" {
+				tags = append(tags, fmt.Sprintf("%s:%q", key, v))
+				isParam = true
+			}
+		}
+		if jsonTag == "-" && !isParam {
+			continue
+		}
+		if hasJSON {
+			tags = append([]string{fmt.Sprintf("json:%q", jsonTag)}, tags...)
+		} else if isParam {
+			tags = append([]string{`json:"-"`}, tags...)
+		}
+		if desc := field.Tag.Get("description"); desc != "" {
+			fmt.Fprintf(&b, "\t// %s\n", desc)
+		}
+		typ := g.typeExpr(field.Type)
+		if field.Anonymous {
+			b.WriteString("\t" + typ)
+		} else {
+			b.WriteString("\t" + field.Name + " " + typ)
+		}
+		if len(tags) > 0 {
+			b.WriteString(" `" + strings.Join(tags, " ") + "`")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func (g *goClientGen) writeTypes(w *bytes.Buffer) {
+	names := make([]string, 0, len(g.types))
+	for name := range g.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "type %s %s\n\n", name, g.declBody(g.types[name]))
+	}
+}
+
+// namedType returns the Go expression for t, declaring it under fallback when
+// it is an anonymous struct (e.g. an inline handler input) so method signatures
+// stay readable.
+func (g *goClientGen) namedType(t reflect.Type, fallback string) string {
+	if t == nil {
+		return "any"
+	}
+	base := dereferenceType(t)
+	if base.Kind() == reflect.Struct && base.Name() == "" {
+		if g.declare(fallback, base) {
+			g.declBody(base)
+		}
+		return fallback
+	}
+	return g.typeExpr(base)
+}
+
+func (g *goClientGen) writeMethod(w *bytes.Buffer, name string, op OpenAPIOperation, shape any) error {
+	inType := op.InputType
+	hasInput := inType != nil && !isEmptyStruct(inType)
+	inExpr := ""
+	if hasInput {
+		inExpr = g.namedType(inType, name+"Input")
+	}
+	outExpr := g.namedType(op.OutputType, name+"Output")
+
+	params := []string{"ctx context.Context"}
+	if hasInput {
+		params = append(params, "in "+inExpr)
+	}
+
+	// Path construction: bound path params come from the input struct, any
+	// placeholder without a matching uri field becomes an extra argument.
+	uriFields := map[string]reflect.StructField{}
+	var queryFields, headerFields []reflect.StructField
+	hasBodyField := false
+	if hasInput {
+		st := dereferenceType(inType)
+		if st.Kind() == reflect.Struct {
+			for i := 0; i < st.NumField(); i++ {
+				f := st.Field(i)
+				if !f.IsExported() || f.Tag.Get("openapi") == "-" {
+					continue
+				}
+				isParam := false
+				if tag := f.Tag.Get("uri"); tag != "" {
+					uriFields[tag] = f
+					isParam = true
+				}
+				if f.Tag.Get("query") != "" {
+					queryFields = append(queryFields, f)
+					isParam = true
+				}
+				if f.Tag.Get("header") != "" {
+					headerFields = append(headerFields, f)
+					isParam = true
+				}
+				if !isParam && f.Tag.Get("json") != "-" {
+					hasBodyField = true
+				}
+			}
+		} else {
+			hasBodyField = true
+		}
+	}
+
+	// Adjacent literal segments are merged so the generated expression reads
+	// like the route, e.g. "/users/" + url.PathEscape(in.ID).
+	var pathExpr []string
+	literal := ""
+	for _, seg := range strings.Split(op.Path, "/") {
+		if seg == "" {
+			continue
+		}
+		param := ""
+		if strings.HasPrefix(seg, ":") {
+			param = strings.TrimSuffix(seg[1:], "?")
+		} else if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			param = seg[1 : len(seg)-1]
+		}
+		literal += "/"
+		if param == "" {
+			literal += seg
+			continue
+		}
+		pathExpr = append(pathExpr, strconv.Quote(literal))
+		literal = ""
+		if f, ok := uriFields[param]; ok {
+			pathExpr = append(pathExpr, "url.PathEscape("+goFormatExpr("in."+f.Name, f.Type, g)+")")
+		} else {
+			arg := goLocalName(param)
+			params = append(params, arg+" string")
+			pathExpr = append(pathExpr, "url.PathEscape("+arg+")")
+		}
+	}
+	if literal != "" || len(pathExpr) == 0 {
+		if literal == "" {
+			literal = "/"
+		}
+		pathExpr = append(pathExpr, strconv.Quote(literal))
+	}
+
+	fmt.Fprintf(w, "// %s calls %s %s.\n", name, op.Method, op.Path)
+	if op.Options.Summary != "" {
+		fmt.Fprintf(w, "//\n// %s\n", op.Options.Summary)
+	}
+	fmt.Fprintf(w, "func (c *%s) %s(%s) (%s, error) {\n", g.cfg.ClientName, name, strings.Join(params, ", "), outExpr)
+	fmt.Fprintf(w, "\tvar out %s\n", outExpr)
+	fmt.Fprintf(w, "\tpath := %s\n", strings.Join(pathExpr, " + "))
+	w.WriteString("\tquery := url.Values{}\n")
+	for _, f := range queryFields {
+		g.writeParamSet(w, "query", f.Tag.Get("query"), f)
+	}
+	w.WriteString("\theader := http.Header{}\n")
+	for _, f := range headerFields {
+		g.writeParamSet(w, "header", f.Tag.Get("header"), f)
+	}
+
+	body := "nil"
+	if hasBodyField && (op.Method == http.MethodPost || op.Method == http.MethodPut || op.Method == http.MethodPatch) {
+		body = "in"
+	}
+	outArg := "&out"
+	if op.Method == http.MethodHead {
+		outArg = "nil"
+	}
+	fmt.Fprintf(w, "\terr := c.do(ctx, %q, path, query, header, %s, %s, decode%sError)\n", op.Method, body, outArg, name)
+	w.WriteString("\treturn out, err\n}\n\n")
+
+	g.writeErrorDecoder(w, name, op, shape)
+	return nil
+}
+
+// writeParamSet emits the statement adding one query or header value. Zero
+// values and nil pointers are omitted so optional parameters stay absent.
+func (g *goClientGen) writeParamSet(w *bytes.Buffer, target, key string, f reflect.StructField) {
+	ref := "in." + f.Name
+	t := f.Type
+	switch {
+	case t.Kind() == reflect.Ptr:
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\t%s.Set(%q, %s)\n\t}\n", ref, target, key, goFormatExpr("*"+ref, t.Elem(), g))
+	case t.Kind() == reflect.Slice:
+		fmt.Fprintf(w, "\tfor _, v := range %s {\n\t\t%s.Add(%q, %s)\n\t}\n", ref, target, key, goFormatExpr("v", t.Elem(), g))
+	case isTimeType(t):
+		fmt.Fprintf(w, "\tif !%s.IsZero() {\n\t\t%s.Set(%q, %s)\n\t}\n", ref, target, key, goFormatExpr(ref, t, g))
+	case t.Kind() == reflect.String:
+		fmt.Fprintf(w, "\tif %s != \"\" {\n\t\t%s.Set(%q, %s)\n\t}\n", ref, target, key, goFormatExpr(ref, t, g))
+	case t.Kind() == reflect.Bool:
+		fmt.Fprintf(w, "\tif %s {\n\t\t%s.Set(%q, \"true\")\n\t}\n", ref, target, key)
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		fmt.Fprintf(w, "\tif %s != 0 {\n\t\t%s.Set(%q, %s)\n\t}\n", ref, target, key, goFormatExpr(ref, t, g))
+	default:
+		fmt.Fprintf(w, "\t%s.Set(%q, %s)\n", target, key, goFormatExpr(ref, t, g))
+	}
+}
+
+// goFormatExpr returns an expression turning ref (of type t) into its string
+// wire form.
+func goFormatExpr(ref string, t reflect.Type, g *goClientGen) string {
+	if isTimeType(t) {
+		g.imports["time"] = true
+		return ref + ".Format(time.RFC3339)"
+	}
+	if t.Kind() == reflect.String {
+		return "string(" + ref + ")"
+	}
+	return "fmt.Sprint(" + ref + ")"
+}
+
+// writeErrorDecoder emits decode<Name>Error, mapping status codes to decoded
+// error bodies. Resolution order mirrors what the server can emit:
+//  1. OpenAPIOptions.Errors, keyed by extractErrorStatusCode
+//  2. 422 → ErrorEnvelope (validation keeps the envelope regardless of shape,
+//     except under Config.ProblemDetails)
+//  3. statuses only the library answers (libraryOnlyErrorStatuses) in
+//     ErrorEnvelope or DefaultErrorShape
+//  4. the handler's concrete TError struct for any other status
+//  5. without one, the other library statuses (libraryErrorStatuses) in the
+//     same default shape
+func (g *goClientGen) writeErrorDecoder(w *bytes.Buffer, name string, op OpenAPIOperation, shape any) {
+	cases := map[int]string{}
+	for _, errInst := range op.Options.Errors {
+		if errInst == nil {
+			continue
+		}
+		code := extractErrorStatusCode(errInst)
+		if _, dup := cases[code]; dup {
+			continue
+		}
+		cases[code] = g.typeExpr(dereferenceType(reflect.TypeOf(errInst)))
+	}
+	envelope := g.typeExpr(reflect.TypeFor[ErrorEnvelope]())
+	if _, ok := shape.(ProblemDetails); ok {
+		envelope = g.typeExpr(reflect.TypeFor[ProblemDetails]())
+	}
+	if _, ok := cases[statusValidationError]; !ok {
+		cases[statusValidationError] = envelope
+	}
+
+	defaultExpr := envelope
+	if shape != nil {
+		defaultExpr = g.typeExpr(dereferenceType(reflect.TypeOf(shape)))
+	}
+	libraryCodes := libraryOnlyErrorStatuses
+	fallback := ""
+	if op.ErrorType != nil && !isEmptyStruct(op.ErrorType) && dereferenceType(op.ErrorType).Kind() == reflect.Struct {
+		fallback = g.typeExpr(dereferenceType(op.ErrorType))
+	} else {
+		libraryCodes = append(libraryCodes, libraryErrorStatuses...)
+	}
+	for _, code := range libraryCodes {
+		if _, ok := cases[code]; !ok {
+			cases[code] = defaultExpr
+		}
+	}
+
+	codes := make([]int, 0, len(cases))
+	for code := range cases {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	// Group status codes sharing a type into one case clause, ordered by the
+	// lowest code of each group.
+	var order []string
+	grouped := map[string][]string{}
+	for _, code := range codes {
+		expr := cases[code]
+		if _, ok := grouped[expr]; !ok {
+			order = append(order, expr)
+		}
+		grouped[expr] = append(grouped[expr], strconv.Itoa(code))
+	}
+
+	fmt.Fprintf(w, "func decode%sError(status int, body []byte) any {\n\tswitch status {\n", name)
+	for _, expr := range order {
+		fmt.Fprintf(w, "\tcase %s:\n\t\treturn decodeAs[%s](body)\n", strings.Join(grouped[expr], ", "), expr)
+	}
+	w.WriteString("\t}\n")
+	if fallback != "" {
+		fmt.Fprintf(w, "\treturn decodeAs[%s](body)\n}\n\n", fallback)
+	} else {
+		w.WriteString("\treturn nil\n}\n\n")
+	}
+}
+
+// libraryOnlyErrorStatuses are answered by the library alone: unsupported
+// version, sunset, body too large, unsupported media type, rate limited.
+var libraryOnlyErrorStatuses = []int{406, 410, 413, 415, 429}
+
+// libraryErrorStatuses are the other statuses the library answers in its
+// default shape. Handlers answer them too, so a concrete TError takes them.
+var libraryErrorStatuses = []int{400, 401, 403, 404, 405, 409, 500, 503}
+
+// goMethodName derives the exported client method name for an operation.
+func goMethodName(op OpenAPIOperation) string {
+	if op.Options.OperationID != "" {
+		return goExportedName(op.Options.OperationID)
+	}
+	name := strings.ToLower(op.Method)
+	for _, seg := range strings.Split(op.Path, "/") {
+		if strings.HasPrefix(seg, ":") || strings.HasPrefix(seg, "{") {
+			name += " by " + strings.Trim(seg, ":{}?")
+		} else {
+			name += " " + seg
+		}
+	}
+	return goExportedName(name)
+}
+
+// goExportedName turns an arbitrary identifier-ish string (camelCase,
+// kebab-case, snake_case, space separated) into an exported Go identifier.
+func goExportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "Op" + name
+	}
+	return name
+}
+
+// goLocalName is goExportedName with a lower-case first letter, used for
+// generated function arguments. Names that would shadow the generated method's
+// locals or collide with a Go keyword get a "Param" suffix.
+func goLocalName(s string) string {
+	name := goExportedName(s)
+	name = strings.ToLower(name[:1]) + name[1:]
+	switch name {
+	case "c", "ctx", "in", "out", "path", "query", "header", "err":
+		return name + "Param"
+	}
+	if token.IsKeyword(name) {
+		return name + "Param"
+	}
+	return name
+}
@@ -0,0 +1,193 @@
+package fiberoapi
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientGenUser struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email" validate:"required,email"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type clientGenGetInput struct {
+	ID     string   `uri:"id" validate:"required"`
+	Expand *bool    `query:"expand"`
+	Fields []string `query:"fields"`
+	Tenant string   `header:"X-Tenant"`
+}
+
+type clientGenCreateInput struct {
+	Org   string `uri:"org"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age"`
+}
+
+type clientGenNotFound struct {
+	StatusCode int    `json:"statusCode"`
+	Message    string `json:"message"`
+}
+
+type clientGenConflict struct {
+	Message string `json:"message"`
+}
+
+func (clientGenConflict) HTTPStatus() int { return 409 }
+
+func newClientGenApp(t *testing.T) *OApiApp {
+	t.Helper()
+	oapi := New(fiber.New(), Config{OpenAPITitle: "Users"})
+	Get(oapi, "/users/:id", func(c fiber.Ctx, in clientGenGetInput) (clientGenUser, error) {
+		return clientGenUser{}, nil
+	}, OpenAPIOptions{OperationID: "getUser", Summary: "Fetch a user", Errors: []any{&clientGenNotFound{StatusCode: 404}}})
+	Post(oapi, "/orgs/:org/users", func(c fiber.Ctx, in clientGenCreateInput) (*clientGenUser, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "create-user", Errors: []any{clientGenConflict{}}})
+	Delete(oapi, "/users/:id/sessions/:session", func(c fiber.Ctx, in struct {
+		ID string `uri:"id"`
+	}) (fiber.Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{})
+	Get(oapi, "/internal", func(c fiber.Ctx, in struct{}) (fiber.Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "internal", Hidden: true})
+	return oapi
+}
+
+// typeCheckGenerated parses and type-checks generated source against the
+// standard library, failing the test on any compile error.
+func typeCheckGenerated(t *testing.T, src []byte) (*ast.File, *types.Package) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "client.go", src, parser.ParseComments)
+	require.NoError(t, err)
+	conf := types.Config{Importer: importer.Default()}
+	pkg, err := conf.Check("client", fset, []*ast.File{file}, nil)
+	require.NoError(t, err, "generated client must compile:\n%s", src)
+	return file, pkg
+}
+
+func TestGenerateGoClient_Compiles(t *testing.T) {
+	src, err := newClientGenApp(t).GenerateGoClient(GoClientConfig{PackageName: "users"})
+	require.NoError(t, err)
+
+	file, pkg := typeCheckGenerated(t, src)
+	assert.Equal(t, "users", file.Name.Name)
+
+	client := pkg.Scope().Lookup("Client")
+	require.NotNil(t, client)
+	mset := types.NewMethodSet(types.NewPointer(client.Type()))
+	for _, name := range []string{"GetUser", "CreateUser", "DeleteUsersByIdSessionsBySession"} {
+		assert.NotNil(t, mset.Lookup(pkg, name), "missing method %s", name)
+	}
+	assert.Nil(t, mset.Lookup(pkg, "Internal"), "hidden operations must not be generated")
+}
+
+func TestGenerateGoClient_Marshalling(t *testing.T) {
+	src, err := newClientGenApp(t).GenerateGoClient(GoClientConfig{})
+	require.NoError(t, err)
+	code := string(src)
+
+	// Path, query and header parameters come from the binding tags.
+	assert.Contains(t, code, `path := "/users/" + url.PathEscape(string(in.ID))`)
+	assert.Contains(t, code, `query.Set("expand", fmt.Sprint(*in.Expand))`)
+	assert.Contains(t, code, `query.Add("fields", string(v))`)
+	assert.Contains(t, code, `header.Set("X-Tenant", string(in.Tenant))`)
+	// Parameter fields are kept out of the JSON body.
+	assert.Contains(t, code, "Org   string `json:\"-\" uri:\"org\"`")
+	// A placeholder without a matching uri field becomes an extra argument.
+	assert.Contains(t, code, "session string")
+	// Anonymous inputs get a named type.
+	assert.Contains(t, code, "type DeleteUsersByIdSessionsBySessionInput struct")
+	assert.Contains(t, code, "CreatedAt time.Time")
+}
+
+func TestGenerateGoClient_ErrorDecoding(t *testing.T) {
+	src, err := newClientGenApp(t).GenerateGoClient(GoClientConfig{})
+	require.NoError(t, err)
+	code := string(src)
+
+	assert.Contains(t, code, "case 404:\n\t\treturn decodeAs[ClientGenNotFound](body)")
+	assert.Contains(t, code, "case 409:\n\t\treturn decodeAs[ClientGenConflict](body)")
+	assert.Contains(t, code, "case 400, 401, 403, 405, 406, 409, 410, 413, 415, 422, 429, 500, 503:\n\t\treturn decodeAs[ErrorEnvelope](body)")
+	assert.Contains(t, code, "type ValidationErrorEntry struct")
+}
+
+func TestGenerateGoClient_DefaultErrorShape(t *testing.T) {
+	oapi := New(fiber.New(), Config{DefaultErrorShape: &UniErr{}})
+	Get(oapi, "/ping", func(c fiber.Ctx, in struct{}) (fiber.Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "ping"})
+
+	src, err := oapi.GenerateGoClient(GoClientConfig{})
+	require.NoError(t, err)
+	typeCheckGenerated(t, src)
+
+	code := string(src)
+	assert.Contains(t, code, "case 400, 401, 403, 404, 405, 406, 409, 410, 413, 415, 429, 500, 503:\n\t\treturn decodeAs[UniErr](body)")
+	assert.Contains(t, code, "case 422:\n\t\treturn decodeAs[ErrorEnvelope](body)")
+	assert.Contains(t, code, "func (c *Client) Ping(ctx context.Context) (Map, error)")
+}
+
+func TestGenerateGoClient_HandlerErrorTypeKeepsItsStatuses(t *testing.T) {
+	oapi := New(fiber.New())
+	Get(oapi, "/ping", func(c fiber.Ctx, in struct{}) (fiber.Map, *clientGenConflict) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "ping"})
+
+	src, err := oapi.GenerateGoClient(GoClientConfig{})
+	require.NoError(t, err)
+	code := string(src)
+	// Statuses only the library answers decode as its envelope; the rest,
+	// 409 and 500 included, fall through to the handler's error type.
+	assert.Contains(t, code, "case 406, 410, 413, 415, 422, 429:\n\t\treturn decodeAs[ErrorEnvelope](body)")
+	assert.Contains(t, code, "\treturn decodeAs[ClientGenConflict](body)\n}")
+}
+
+func TestGenerateGoClient_NameCollisions(t *testing.T) {
+	type Map map[string]string
+	oapi := New(fiber.New())
+	Get(oapi, "/a", func(c fiber.Ctx, in struct{}) (fiber.Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "a"})
+	Get(oapi, "/b", func(c fiber.Ctx, in struct{}) (Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "b"})
+	_, err := oapi.GenerateGoClient(GoClientConfig{})
+	assert.EqualError(t, err, "generated client: type name Map is used by both "+
+		"github.com/gofiber/fiber/v3.Map and github.com/labbs/fiber-oapi/v3.Map; rename one of them")
+
+	type APIError struct {
+		Reason string `json:"reason"`
+	}
+	type Client struct {
+		Name string `json:"name"`
+	}
+	oapi = New(fiber.New())
+	Get(oapi, "/clients", func(c fiber.Ctx, in struct{}) (Client, *APIError) {
+		return Client{}, nil
+	}, OpenAPIOptions{OperationID: "getClient"})
+	_, err = oapi.GenerateGoClient(GoClientConfig{ClientName: "UsersClient"})
+	assert.ErrorContains(t, err, "clashes with the client's own APIError")
+
+	// The runtime names follow GoClientConfig.ClientName.
+	oapi = New(fiber.New())
+	Get(oapi, "/clients", func(c fiber.Ctx, in struct{}) (Client, error) {
+		return Client{}, nil
+	}, OpenAPIOptions{OperationID: "getClient"})
+	_, err = oapi.GenerateGoClient(GoClientConfig{})
+	assert.ErrorContains(t, err, "clashes with the client's own Client")
+	src, err := oapi.GenerateGoClient(GoClientConfig{ClientName: "UsersClient"})
+	require.NoError(t, err)
+	typeCheckGenerated(t, src)
+}
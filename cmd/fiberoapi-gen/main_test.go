@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDriver(t *testing.T) {
+	src, err := renderDriver(options{
+		Lang:       "go",
+		Pkg:        "example.com/svc/api",
+		Func:       "Routes",
+		Out:        "/tmp/client.go",
+		Package:    "svcclient",
+		ClientName: "API",
+	})
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, `target "example.com/svc/api"`)
+	assert.Contains(t, code, "fiberoapi.LoadRoutes(target.Routes)")
+	assert.Contains(t, code, `PackageName: "svcclient"`)
+	assert.Contains(t, code, `ClientName:  "API"`)
+	assert.True(t, strings.Contains(code, `out := "/tmp/client.go"`))
+}
+
+func TestRenderDriverTypeScript(t *testing.T) {
+	src, err := renderDriver(options{Lang: "ts", Pkg: "example.com/svc/api", Func: "RegisterRoutes", Out: "-", ClientName: "Client"})
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "oapi.GenerateTypeScript(fiberoapi.TypeScriptConfig{")
+	assert.NotContains(t, code, "GenerateGoClient")
+}
+
+func TestRenderDriverOpenAPI(t *testing.T) {
+	src, err := renderDriver(options{Lang: "openapi", Format: "yaml", Pkg: "example.com/svc/api", Func: "RegisterRoutes", Out: "/tmp/openapi.yaml"})
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "oapi.LintSpec()")
+	assert.Contains(t, code, `oapi.MarshalSpec("yaml")`)
+}
+
+func TestRunRejectsUnknownLang(t *testing.T) {
+	err := run(options{Lang: "rust", Pkg: "example.com/svc/api", Out: "-"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-lang")
+}
+
+func TestRunRequiresPkg(t *testing.T) {
+	err := run(options{Out: "-"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-pkg")
+}
@@ -6,17 +6,9 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v3"
 )
 
-// Global validator instance
-var validate *validator.Validate
-
-func init() {
-	validate = validator.New()
-}
-
 // inputShape caches per-type metadata used at request time so we avoid
 // re-introspecting the input struct on every request. Built once via sync.OnceValue.
 type inputShape struct {
@@ -47,14 +39,54 @@ func shapeFor[TInput any]() *inputShape {
 func parseInput[TInput any](app *OApiApp, c fiber.Ctx, path string, options *OpenAPIOptions) (TInput, error) {
 	var input TInput
 
+	// Config.Hooks observes each phase; run is nil without hooks.
+	run := hookRunFrom(c)
+	run.begin()
+	err := bindInput(app, c, options, &input)
+	run.parsed(c, err)
+	if err != nil {
+		return input, err
+	}
+
+	// Validate input if enabled in configuration
+	if app.Config().EnableValidation {
+		err := validateInput(app, c, &input)
+		run.validated(c, err)
+		if err != nil {
+			return input, err
+		}
+	}
+
+	// Validate authorization if enabled in configuration and not disabled for this route
+	if app.Config().EnableAuthorization && options != nil {
+		if securityValue, ok := options.Security.(string); ok && securityValue == "disabled" {
+			// Skip authorization for this route
+		} else {
+			cfg := app.Config()
+			if routeSecurity, ok := options.Security.([]map[string][]string); ok && len(routeSecurity) > 0 {
+				cfg.DefaultSecurity = routeSecurity
+			}
+			err := validateAuthorization(c, input, cfg.AuthService, &cfg, options.RequiredRoles, options.RequireAllRoles)
+			run.authorized(c, err)
+			if err != nil {
+				return input, err
+			}
+		}
+	}
+
+	return input, nil
+}
+
+// bindInput fills input from the path, query, body and headers of the request.
+func bindInput[TInput any](app *OApiApp, c fiber.Ctx, options *OpenAPIOptions, input *TInput) error {
 	shape := shapeFor[TInput]()
 
 	if shape.isStruct {
-		if err := c.Bind().URI(&input); err != nil {
-			return input, err
+		if err := c.Bind().URI(input); err != nil {
+			return err
 		}
-		if err := c.Bind().Query(&input); err != nil {
-			return input, err
+		if err := c.Bind().Query(input); err != nil {
+			return err
 		}
 	}
 
@@ -64,88 +96,55 @@ func parseInput[TInput any](app *OApiApp, c fiber.Ctx, path string, options *Ope
 	// field is also sent in the body without a json:"-" tag).
 	method := c.Method()
 	if method == "POST" || method == "PUT" || method == "PATCH" {
+		if err := checkRequestBody(c, options); err != nil {
+			return err
+		}
+
 		bodyLength := len(c.Body())
 		contentType := c.Get("Content-Type")
 
+		if bodyLength > 0 && strings.Contains(contentType, "json") && strictJSONEnabled(app.config, options) {
+			if err := checkStrictJSON(c.Body(), reflect.TypeOf(*input)); err != nil {
+				return err
+			}
+		}
+
 		if bodyLength > 0 || strings.Contains(contentType, "application/json") || strings.Contains(contentType, "application/x-www-form-urlencoded") {
-			if err := c.Bind().Body(&input); err != nil {
+			if err := c.Bind().Body(input); err != nil {
 				// For POST without a body, tolerate the parsing failure.
 				if bodyLength == 0 && method == "POST" {
 					// no-op
 				} else if wrapped := wrapJSONTypeError(err); wrapped != nil {
 					// Type-mismatch errors get a friendly Error() while staying
 					// errors.As-recoverable down to the original *json.UnmarshalTypeError.
-					return input, wrapped
+					return wrapped
 				} else {
-					return input, err
+					return err
 				}
 			}
 		}
 	}
 
 	if shape.isStruct {
-		if err := c.Bind().Header(&input); err != nil {
-			return input, err
-		}
-	}
-
-	// Validate input if enabled in configuration
-	if app.Config().EnableValidation {
-		if err := validate.Struct(input); err != nil {
-			return input, err
-		}
-	}
-
-	// Validate authorization if enabled in configuration and not disabled for this route
-	if app.Config().EnableAuthorization && options != nil {
-		if securityValue, ok := options.Security.(string); ok && securityValue == "disabled" {
-			// Skip authorization for this route
-		} else {
-			cfg := app.Config()
-			if routeSecurity, ok := options.Security.([]map[string][]string); ok && len(routeSecurity) > 0 {
-				cfg.DefaultSecurity = routeSecurity
-			}
-			if err := validateAuthorization(c, input, cfg.AuthService, &cfg, options.RequiredRoles, options.RequireAllRoles); err != nil {
-				return input, err
-			}
+		if err := c.Bind().Header(input); err != nil {
+			return err
 		}
 	}
 
-	return input, nil
+	return nil
 }
 
-// Function to handle custom errors
-func handleCustomError(c fiber.Ctx, customErr interface{}) error {
-	// Use reflection to extract error information
-	errValue := reflect.ValueOf(customErr)
-
-	// Handle pointers - get the element they point to
-	if errValue.Kind() == reflect.Ptr {
-		if errValue.IsNil() {
-			return c.Status(500).JSON(fiber.Map{"error": "Internal server error"})
-		}
-		errValue = errValue.Elem()
+// validateInput runs the validate tags of input, then its own Validate method
+// with a value or pointer receiver.
+func validateInput[TInput any](app *OApiApp, c fiber.Ctx, input *TInput) error {
+	if err := app.validation.validate.Struct(*input); err != nil {
+		return err
 	}
-
-	// Assume your error struct has fields like StatusCode and Message
-	statusCode := 500 // default
-	if errValue.Kind() == reflect.Struct {
-		if field := errValue.FieldByName("StatusCode"); field.IsValid() && field.CanInt() {
-			statusCode = int(field.Int())
-		} else if field := errValue.FieldByName("Code"); field.IsValid() && field.CanInt() {
-			statusCode = int(field.Int())
-		}
+	target := any(*input)
+	if _, ok := target.(InputValidator); !ok {
+		target = input
 	}
-
-	// Return the error as JSON
-	if err := c.Status(statusCode).JSON(customErr); err != nil {
-		if fallbackErr := c.Status(500).JSON(fiber.Map{"error": "Failed to serialize error response"}); fallbackErr != nil {
-			// Both serializations failed, return original error to Fiber
-			return err
-		}
-		return nil
-	}
-	return nil
+	return runInputValidator(c, target)
 }
 
 // Utility to check if a value is zero. Handles three edge cases beyond the
@@ -181,8 +180,12 @@ func validatePathParams[T any](path string) error {
 		return nil
 	}
 
-	// Extract Fiber path parameters (:param format)
+	// Extract Fiber path parameters (:param format), without their optional
+	// marker or constraint
 	pathParams := extractFiberPathParams(path)
+	for i, p := range pathParams {
+		pathParams[i] = pathParamName(p)
+	}
 
 	// Check that each field with "path" tag exists in the path
 	for i := 0; i < inputType.NumField(); i++ {
@@ -269,6 +272,9 @@ func extractParametersFromStruct(inputType reflect.Type) []map[string]interface{
 				"description": getFieldDescription(field, "Path parameter"),
 				"schema":      getSchemaForType(field.Type),
 			}
+			if isDeprecatedField(field.Tag.Get("deprecated")) {
+				param["deprecated"] = true
+			}
 			parameters = append(parameters, param)
 		}
 
@@ -283,6 +289,9 @@ func extractParametersFromStruct(inputType reflect.Type) []map[string]interface{
 				"description": getFieldDescription(field, "Query parameter"),
 				"schema":      getSchemaForType(field.Type),
 			}
+			if isDeprecatedField(field.Tag.Get("deprecated")) {
+				param["deprecated"] = true
+			}
 			parameters = append(parameters, param)
 		}
 
@@ -303,6 +312,9 @@ func extractParametersFromStruct(inputType reflect.Type) []map[string]interface{
 				"description": getFieldDescription(field, "Header parameter"),
 				"schema":      getSchemaForType(field.Type),
 			}
+			if isDeprecatedField(field.Tag.Get("deprecated")) {
+				param["deprecated"] = true
+			}
 			parameters = append(parameters, param)
 		}
 	}
@@ -0,0 +1,497 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Contract difference kinds reported by VerifyAgainst.
+const (
+	ContractMissingOperation    = "missing_operation"     // in the contract, not registered
+	ContractExtraOperation      = "extra_operation"       // registered, not in the contract
+	ContractParameterMismatch   = "parameter_mismatch"    // path / query / header / cookie parameter differs
+	ContractRequestBodyMismatch = "request_body_mismatch" // request body presence, media type or schema differs
+	ContractResponseMismatch    = "response_mismatch"     // response status, media type or schema differs
+)
+
+// ContractDiff is one divergence between the authored contract and the
+// generated spec.
+type ContractDiff struct {
+	Kind      string // one of the Contract* constants
+	Operation string // e.g. "GET /users/{id}", as written in the contract when present
+	Location  string // e.g. "parameters.query.limit", "requestBody", "responses.200"; empty for operation-level diffs
+	Message   string
+}
+
+func (d ContractDiff) String() string {
+	if d.Location == "" {
+		return fmt.Sprintf("%s: %s", d.Operation, d.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", d.Operation, d.Location, d.Message)
+}
+
+// ContractError is returned by VerifyAgainst when the implementation diverges
+// from the contract.
+type ContractError struct {
+	Diffs []ContractDiff
+}
+
+func (e *ContractError) Error() string {
+	lines := make([]string, len(e.Diffs))
+	for i, d := range e.Diffs {
+		lines[i] = d.String()
+	}
+	return fmt.Sprintf("implementation diverges from contract (%d difference(s)):\n  %s", len(e.Diffs), strings.Join(lines, "\n  "))
+}
+
+// ContractOptions relaxes VerifyAgainst for contracts that intentionally
+// describe a subset of the implementation.
+type ContractOptions struct {
+	AllowExtraOperations bool // do not report registered operations absent from the contract
+	AllowExtraResponses  bool // do not report response status codes absent from the contract (e.g. the library's default 400/422)
+}
+
+// VerifyAgainst compares the generated spec with an authored OpenAPI document
+// (JSON or YAML) and returns a *ContractError listing missing operations,
+// extra operations, and mismatches in parameters, request bodies and
+// responses. It returns nil when the implementation matches the contract.
+//
+// Operations are matched by method and path template; placeholder names do not
+// have to match for the operation to be found, but differing names are then
+// reported as parameter mismatches. Schemas are compared after resolving
+// local $refs, on the keywords that define the wire contract (type, format,
+// enum, required, nullable, properties, items, additionalProperties and the
+// length / range / pattern constraints). Descriptions and examples are ignored.
+func (o *OApiApp) VerifyAgainst(specBytes []byte, opts ...ContractOptions) error {
+	var opt ContractOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(specBytes, &raw); err != nil {
+		return fmt.Errorf("parse contract: %w", err)
+	}
+	contract, ok := normalizeYAML(raw).(map[string]any)
+	if !ok {
+		return fmt.Errorf("parse contract: document is not an object")
+	}
+	generated, err := roundTripJSON(o.GenerateOpenAPISpec())
+	if err != nil {
+		return err
+	}
+
+	v := &contractVerifier{want: contract, got: generated, opt: opt}
+	v.compareOperations()
+	if len(v.diffs) == 0 {
+		return nil
+	}
+	return &ContractError{Diffs: v.diffs}
+}
+
+// normalizeYAML converts the map[any]any nodes yaml.v3 produces for
+// non-string keys (e.g. unquoted response codes) into map[string]any, then
+// round-trips through JSON so numbers compare the same way on both sides.
+func normalizeYAML(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[k] = normalizeYAML(val)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			out[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			out[i] = normalizeYAML(val)
+		}
+		return out
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+func roundTripJSON(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var placeholderRegex = regexp.MustCompile(`\{[^}/]+\}`)
+
+// pathShape erases placeholder names so /users/{id} and /users/{userId} match.
+func pathShape(path string) string {
+	return placeholderRegex.ReplaceAllString(path, "{}")
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type contractOperation struct {
+	key  string // METHOD + shape
+	name string // "GET /users/{id}"
+	op   map[string]any
+	path map[string]any // path item, for path-level parameters
+}
+
+func indexOperations(spec map[string]any) (map[string]contractOperation, []string) {
+	ops := map[string]contractOperation{}
+	var order []string
+	paths, _ := spec["paths"].(map[string]any)
+	pathKeys := sortedKeys(paths)
+	for _, p := range pathKeys {
+		item, _ := paths[p].(map[string]any)
+		for _, m := range httpMethods {
+			op, ok := item[m].(map[string]any)
+			if !ok {
+				continue
+			}
+			key := strings.ToUpper(m) + " " + pathShape(p)
+			ops[key] = contractOperation{key: key, name: strings.ToUpper(m) + " " + p, op: op, path: item}
+			order = append(order, key)
+		}
+	}
+	return ops, order
+}
+
+type contractVerifier struct {
+	want, got map[string]any
+	opt       ContractOptions
+	diffs     []ContractDiff
+}
+
+func (v *contractVerifier) add(kind, op, loc, msg string) {
+	v.diffs = append(v.diffs, ContractDiff{Kind: kind, Operation: op, Location: loc, Message: msg})
+}
+
+func (v *contractVerifier) compareOperations() {
+	wantOps, wantOrder := indexOperations(v.want)
+	gotOps, gotOrder := indexOperations(v.got)
+
+	for _, key := range wantOrder {
+		w := wantOps[key]
+		g, ok := gotOps[key]
+		if !ok {
+			v.add(ContractMissingOperation, w.name, "", "operation is in the contract but not registered")
+			continue
+		}
+		v.compareParameters(w, g)
+		v.compareRequestBody(w, g)
+		v.compareResponses(w, g)
+	}
+	if v.opt.AllowExtraOperations {
+		return
+	}
+	for _, key := range gotOrder {
+		if _, ok := wantOps[key]; !ok {
+			v.add(ContractExtraOperation, gotOps[key].name, "", "operation is registered but not in the contract")
+		}
+	}
+}
+
+// parameterKey identifies a parameter; header names are case-insensitive.
+func parameterKey(p map[string]any) string {
+	in, _ := p["in"].(string)
+	name, _ := p["name"].(string)
+	if in == "header" {
+		name = strings.ToLower(name)
+	}
+	return in + "." + name
+}
+
+func (v *contractVerifier) collectParameters(spec map[string]any, co contractOperation) map[string]map[string]any {
+	out := map[string]map[string]any{}
+	for _, src := range []any{co.path["parameters"], co.op["parameters"]} {
+		list, _ := src.([]any)
+		for _, item := range list {
+			p, ok := resolveRef(spec, item).(map[string]any)
+			if !ok {
+				continue
+			}
+			out[parameterKey(p)] = p
+		}
+	}
+	return out
+}
+
+func (v *contractVerifier) compareParameters(w, g contractOperation) {
+	wantParams := v.collectParameters(v.want, w)
+	gotParams := v.collectParameters(v.got, g)
+
+	for _, key := range sortedKeys(wantParams) {
+		wp := wantParams[key]
+		loc := "parameters." + key
+		gp, ok := gotParams[key]
+		if !ok {
+			v.add(ContractParameterMismatch, w.name, loc, "parameter is in the contract but not accepted by the implementation")
+			continue
+		}
+		if asBool(wp["required"]) != asBool(gp["required"]) {
+			v.add(ContractParameterMismatch, w.name, loc, fmt.Sprintf("required is %v in the contract but %v in the implementation", asBool(wp["required"]), asBool(gp["required"])))
+		}
+		if msgs := compareSchemas(v.want, v.got, wp["schema"], gp["schema"], "schema", nil); len(msgs) > 0 {
+			for _, m := range msgs {
+				v.add(ContractParameterMismatch, w.name, loc, m)
+			}
+		}
+	}
+	for _, key := range sortedKeys(gotParams) {
+		if _, ok := wantParams[key]; !ok {
+			v.add(ContractParameterMismatch, w.name, "parameters."+key, "parameter is accepted by the implementation but not in the contract")
+		}
+	}
+}
+
+func (v *contractVerifier) compareRequestBody(w, g contractOperation) {
+	wb, _ := resolveRef(v.want, w.op["requestBody"]).(map[string]any)
+	gb, _ := resolveRef(v.got, g.op["requestBody"]).(map[string]any)
+	switch {
+	case wb == nil && gb == nil:
+		return
+	case wb == nil:
+		v.add(ContractRequestBodyMismatch, w.name, "requestBody", "implementation expects a request body the contract does not define")
+		return
+	case gb == nil:
+		v.add(ContractRequestBodyMismatch, w.name, "requestBody", "contract defines a request body the implementation does not read")
+		return
+	}
+	if asBool(wb["required"]) != asBool(gb["required"]) {
+		v.add(ContractRequestBodyMismatch, w.name, "requestBody", fmt.Sprintf("required is %v in the contract but %v in the implementation", asBool(wb["required"]), asBool(gb["required"])))
+	}
+	v.compareContent(ContractRequestBodyMismatch, w.name, "requestBody", wb, gb)
+}
+
+func (v *contractVerifier) compareResponses(w, g contractOperation) {
+	wr, _ := w.op["responses"].(map[string]any)
+	gr, _ := g.op["responses"].(map[string]any)
+	for _, code := range sortedKeys(wr) {
+		loc := "responses." + code
+		gresp, ok := gr[code]
+		if !ok {
+			v.add(ContractResponseMismatch, w.name, loc, "response is in the contract but not produced by the implementation")
+			continue
+		}
+		wm, _ := resolveRef(v.want, wr[code]).(map[string]any)
+		gm, _ := resolveRef(v.got, gresp).(map[string]any)
+		v.compareContent(ContractResponseMismatch, w.name, loc, wm, gm)
+	}
+	if v.opt.AllowExtraResponses {
+		return
+	}
+	for _, code := range sortedKeys(gr) {
+		if _, ok := wr[code]; !ok {
+			v.add(ContractResponseMismatch, w.name, "responses."+code, "response is produced by the implementation but not in the contract")
+		}
+	}
+}
+
+// compareContent compares the media types and their schemas of a request body
+// or response object.
+func (v *contractVerifier) compareContent(kind, op, loc string, want, got map[string]any) {
+	wc, _ := want["content"].(map[string]any)
+	gc, _ := got["content"].(map[string]any)
+	for _, mt := range sortedKeys(wc) {
+		gmt, ok := gc[mt].(map[string]any)
+		if !ok {
+			v.add(kind, op, loc, fmt.Sprintf("media type %s is in the contract but not in the implementation", mt))
+			continue
+		}
+		wmt, _ := wc[mt].(map[string]any)
+		for _, m := range compareSchemas(v.want, v.got, wmt["schema"], gmt["schema"], "schema", nil) {
+			v.add(kind, op, loc+"."+mt, m)
+		}
+	}
+	for _, mt := range sortedKeys(gc) {
+		if _, ok := wc[mt]; !ok {
+			v.add(kind, op, loc, fmt.Sprintf("media type %s is in the implementation but not in the contract", mt))
+		}
+	}
+}
+
+// resolveRef follows a local "#/..." $ref (repeatedly) within doc.
+func resolveRef(doc map[string]any, node any) any {
+	for range 32 { // bounded to survive self-referencing aliases
+		m, ok := node.(map[string]any)
+		if !ok {
+			return node
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok || !strings.HasPrefix(ref, "#/") {
+			return node
+		}
+		var cur any = doc
+		for _, seg := range strings.Split(ref[2:], "/") {
+			seg = strings.ReplaceAll(strings.ReplaceAll(seg, "~1", "/"), "~0", "~")
+			cm, ok := cur.(map[string]any)
+			if !ok {
+				return nil
+			}
+			cur = cm[seg]
+		}
+		node = cur
+	}
+	return node
+}
+
+// schemaKeywords are compared by value; everything else except the structural
+// keywords handled explicitly (properties, items, additionalProperties,
+// required, enum) is treated as documentation.
+var schemaKeywords = []string{"type", "format", "nullable", "minLength", "maxLength", "minimum", "maximum", "pattern", "minItems", "maxItems"}
+
+// compareSchemas returns one message per divergence between two schemas,
+// prefixed with a dotted path. visiting breaks $ref cycles.
+func compareSchemas(wantDoc, gotDoc map[string]any, want, got any, path string, visiting map[string]bool) []string {
+	wantRef, _ := refOf(want)
+	gotRef, _ := refOf(got)
+	if wantRef != "" && gotRef != "" {
+		pair := wantRef + "|" + gotRef
+		if visiting[pair] {
+			return nil
+		}
+		next := make(map[string]bool, len(visiting)+1)
+		for k := range visiting {
+			next[k] = true
+		}
+		next[pair] = true
+		visiting = next
+	}
+	w, _ := resolveRef(wantDoc, want).(map[string]any)
+	g, _ := resolveRef(gotDoc, got).(map[string]any)
+	if w == nil && g == nil {
+		return nil
+	}
+	if w == nil {
+		return []string{path + ": implementation defines a schema the contract does not"}
+	}
+	if g == nil {
+		return []string{path + ": contract defines a schema the implementation does not"}
+	}
+
+	var msgs []string
+	for _, kw := range schemaKeywords {
+		wv, wok := w[kw]
+		gv, gok := g[kw]
+		if !wok && !gok {
+			continue
+		}
+		if !reflect.DeepEqual(wv, gv) {
+			msgs = append(msgs, fmt.Sprintf("%s.%s: contract has %s, implementation has %s", path, kw, describe(wv, wok), describe(gv, gok)))
+		}
+	}
+	if !sameStringSet(w["enum"], g["enum"]) {
+		msgs = append(msgs, fmt.Sprintf("%s.enum: contract has %s, implementation has %s", path, describe(w["enum"], w["enum"] != nil), describe(g["enum"], g["enum"] != nil)))
+	}
+	if !sameStringSet(w["required"], g["required"]) {
+		msgs = append(msgs, fmt.Sprintf("%s.required: contract has %s, implementation has %s", path, describe(w["required"], w["required"] != nil), describe(g["required"], g["required"] != nil)))
+	}
+
+	wp, _ := w["properties"].(map[string]any)
+	gp, _ := g["properties"].(map[string]any)
+	for _, name := range sortedKeys(wp) {
+		if _, ok := gp[name]; !ok {
+			msgs = append(msgs, fmt.Sprintf("%s.properties.%s: missing from the implementation", path, name))
+			continue
+		}
+		msgs = append(msgs, compareSchemas(wantDoc, gotDoc, wp[name], gp[name], path+".properties."+name, visiting)...)
+	}
+	for _, name := range sortedKeys(gp) {
+		if _, ok := wp[name]; !ok {
+			msgs = append(msgs, fmt.Sprintf("%s.properties.%s: not in the contract", path, name))
+		}
+	}
+
+	if w["items"] != nil || g["items"] != nil {
+		msgs = append(msgs, compareSchemas(wantDoc, gotDoc, w["items"], g["items"], path+".items", visiting)...)
+	}
+	// additionalProperties may be a bool or a schema.
+	wa, wIsSchema := w["additionalProperties"].(map[string]any)
+	ga, gIsSchema := g["additionalProperties"].(map[string]any)
+	switch {
+	case wIsSchema && gIsSchema:
+		msgs = append(msgs, compareSchemas(wantDoc, gotDoc, wa, ga, path+".additionalProperties", visiting)...)
+	case !reflect.DeepEqual(w["additionalProperties"], g["additionalProperties"]):
+		_, wok := w["additionalProperties"]
+		_, gok := g["additionalProperties"]
+		msgs = append(msgs, fmt.Sprintf("%s.additionalProperties: contract has %s, implementation has %s", path, describe(w["additionalProperties"], wok), describe(g["additionalProperties"], gok)))
+	}
+	return msgs
+}
+
+func refOf(node any) (string, bool) {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
+
+func describe(v any, present bool) string {
+	if !present {
+		return "nothing"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// sameStringSet compares two JSON arrays as unordered sets of their printed
+// values.
+func sameStringSet(a, b any) bool {
+	as, _ := a.([]any)
+	bs, _ := b.([]any)
+	if len(as) != len(bs) {
+		return false
+	}
+	seen := make(map[string]int, len(as))
+	for _, v := range as {
+		seen[fmt.Sprint(v)]++
+	}
+	for _, v := range bs {
+		k := fmt.Sprint(v)
+		if seen[k] == 0 {
+			return false
+		}
+		seen[k]--
+	}
+	return true
+}
+
+func asBool(v any) bool {
+	b, _ := v.(bool)
+	return b
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,144 @@
+package fiberoapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type contractUserInput struct {
+	ID     string `uri:"id" validate:"required"`
+	Fields string `query:"fields"`
+}
+
+type contractUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type contractCreateInput struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newContractApp() *OApiApp {
+	oapi := New(fiber.New())
+	Get(oapi, "/users/:id", func(c fiber.Ctx, in contractUserInput) (contractUser, error) {
+		return contractUser{ID: in.ID}, nil
+	}, OpenAPIOptions{OperationID: "getUser"})
+	Post(oapi, "/users", func(c fiber.Ctx, in contractCreateInput) (contractUser, error) {
+		return contractUser{Name: in.Name}, nil
+	}, OpenAPIOptions{OperationID: "createUser"})
+	return oapi
+}
+
+func contractDiffs(t *testing.T, err error) []ContractDiff {
+	t.Helper()
+	var cerr *ContractError
+	require.True(t, errors.As(err, &cerr), "expected *ContractError, got %v", err)
+	return cerr.Diffs
+}
+
+func TestVerifyAgainst_GeneratedSpecMatchesItself(t *testing.T) {
+	oapi := newContractApp()
+
+	raw, err := oapi.MarshalSpec(SpecFormatJSON)
+	require.NoError(t, err)
+	assert.NoError(t, oapi.VerifyAgainst(raw))
+
+	raw, err = oapi.MarshalSpec(SpecFormatYAML)
+	require.NoError(t, err)
+	assert.NoError(t, oapi.VerifyAgainst(raw))
+}
+
+const contractYAML = `
+openapi: 3.0.0
+info: {title: users, version: "1"}
+paths:
+  /users/{userId}:
+    get:
+      parameters:
+        - {name: userId, in: path, required: true, schema: {type: string}}
+        - {name: fields, in: query, schema: {type: integer}}
+      responses:
+        200:
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+  /users/{id}:
+    delete:
+      responses:
+        204: {description: deleted}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id]
+      properties:
+        id: {type: string}
+        email: {type: string, format: email}
+`
+
+func TestVerifyAgainst_ReportsDifferences(t *testing.T) {
+	oapi := newContractApp()
+	diffs := contractDiffs(t, oapi.VerifyAgainst([]byte(contractYAML), ContractOptions{AllowExtraResponses: true}))
+
+	var got []string
+	for _, d := range diffs {
+		got = append(got, d.Kind+" "+d.String())
+	}
+	joined := strings.Join(got, "\n")
+
+	assert.Contains(t, joined, "missing_operation DELETE /users/{id}")
+	assert.Contains(t, joined, "extra_operation POST /users")
+	// Placeholder names differ: the operation is matched but the parameter is not.
+	assert.Contains(t, joined, "parameter_mismatch GET /users/{userId} parameters.path.userId: parameter is in the contract but not accepted")
+	assert.Contains(t, joined, "parameter_mismatch GET /users/{userId} parameters.path.id: parameter is accepted by the implementation but not in the contract")
+	assert.Contains(t, joined, `parameters.query.fields: schema.type: contract has "integer", implementation has "string"`)
+	assert.Contains(t, joined, "responses.200.application/json: schema.required")
+	assert.Contains(t, joined, "schema.properties.email: missing from the implementation")
+	assert.Contains(t, joined, "schema.properties.name: not in the contract")
+	assert.NotContains(t, joined, "responses.400", "AllowExtraResponses hides library default responses")
+}
+
+func TestVerifyAgainst_Options(t *testing.T) {
+	oapi := newContractApp()
+	raw, err := oapi.MarshalSpec(SpecFormatJSON)
+	require.NoError(t, err)
+
+	// A contract describing only GET /users/{id} and its 200 response.
+	trimmed := strings.Replace(string(raw), `"post"`, `"x-post"`, 1)
+	diffs := contractDiffs(t, oapi.VerifyAgainst([]byte(trimmed)))
+	require.Len(t, diffs, 1)
+	assert.Equal(t, ContractExtraOperation, diffs[0].Kind)
+	assert.Equal(t, "POST /users", diffs[0].Operation)
+
+	assert.NoError(t, oapi.VerifyAgainst([]byte(trimmed), ContractOptions{AllowExtraOperations: true}))
+}
+
+func TestVerifyAgainst_RequestBody(t *testing.T) {
+	oapi := New(fiber.New())
+	Post(oapi, "/users", func(c fiber.Ctx, in contractCreateInput) (contractUser, error) {
+		return contractUser{}, nil
+	}, OpenAPIOptions{})
+
+	contract := `{"openapi":"3.0.0","paths":{"/users":{"post":{
+		"requestBody":{"required":true,"content":{"application/json":{"schema":{
+			"type":"object","required":["name"],"properties":{"name":{"type":"string","minLength":1}}}}}},
+		"responses":{}}}}}`
+	diffs := contractDiffs(t, oapi.VerifyAgainst([]byte(contract), ContractOptions{AllowExtraResponses: true}))
+	require.Len(t, diffs, 1)
+	assert.Equal(t, ContractRequestBodyMismatch, diffs[0].Kind)
+	assert.Equal(t, "requestBody.application/json", diffs[0].Location)
+	assert.Contains(t, diffs[0].Message, "schema.properties.name.minLength")
+}
+
+func TestVerifyAgainst_InvalidDocument(t *testing.T) {
+	oapi := newContractApp()
+	assert.Error(t, oapi.VerifyAgainst([]byte("- just\n- a list\n")))
+	assert.Error(t, oapi.VerifyAgainst([]byte("{not yaml")))
+}
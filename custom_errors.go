@@ -99,24 +99,24 @@ func extractErrorDescription(v any, code int) string {
 // errorSchemaRef returns the schema reference for a declared error type. Named
 // types are exposed via $ref so the spec deduplicates the schema; anonymous
 // types fall back to an inline schema.
-func errorSchemaRef(t reflect.Type) map[string]interface{} {
+func errorSchemaRef(t reflect.Type, rules *validationRules) map[string]interface{} {
 	if t == nil {
 		return map[string]interface{}{"type": "object"}
 	}
 	t = dereferenceType(t)
 	if shouldInlineOperationSchema(t) {
-		return generateSchema(t)
+		return generateSchema(t, rules)
 	}
 	if name := getTypeName(t); name != "" {
 		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
 	}
-	return generateSchema(t)
+	return generateSchema(t, rules)
 }
 
 // buildErrorResponse turns a single declared error instance into an OpenAPI
 // response object. The status code is returned alongside so the caller can
 // place it under the right key.
-func buildErrorResponse(errInst any) (statusCode int, response map[string]interface{}) {
+func buildErrorResponse(errInst any, rules *validationRules) (statusCode int, response map[string]interface{}) {
 	statusCode = extractErrorStatusCode(errInst)
 	description := extractErrorDescription(errInst, statusCode)
 	t := reflect.TypeOf(errInst)
@@ -124,7 +124,7 @@ func buildErrorResponse(errInst any) (statusCode int, response map[string]interf
 		"description": description,
 		"content": map[string]interface{}{
 			"application/json": map[string]interface{}{
-				"schema":  errorSchemaRef(t),
+				"schema":  errorSchemaRef(t, rules),
 				"example": errInst,
 			},
 		},
@@ -132,6 +132,18 @@ func buildErrorResponse(errInst any) (statusCode int, response map[string]interf
 	return statusCode, response
 }
 
+// DeclaredErrorType returns the type of the error declared in Options.Errors
+// for the given status code, or nil when none is declared for it. The status
+// is resolved the same way as in the spec (see extractErrorStatusCode).
+func (op OpenAPIOperation) DeclaredErrorType(status int) reflect.Type {
+	for _, errInst := range op.Options.Errors {
+		if errInst != nil && extractErrorStatusCode(errInst) == status {
+			return reflect.TypeOf(errInst)
+		}
+	}
+	return nil
+}
+
 // statusCodeKey formats a status code as the string key expected by the
 // OpenAPI responses map. We use the concrete code (e.g. "404") rather than
 // the "4XX" wildcard so each declared error has its own slot.
@@ -162,19 +174,25 @@ type errorCategory struct {
 	Details string // optional secondary context (joined field list, source error, ...)
 }
 
-// isValidationError reports whether err is a go-playground/validator error.
-// Used to keep validation responses on the rich ErrorEnvelope shape even when
-// the user opted into a flat DefaultErrorShape — per-field info (loc /
-// constraint / field) only makes sense in the array-of-entries shape.
+// isValidationError reports whether err is a go-playground/validator error or
+// issues returned by an InputValidator. Used to keep validation responses on
+// the rich ErrorEnvelope shape even when the user opted into a flat
+// DefaultErrorShape — per-field info (loc / constraint / field) only makes
+// sense in the array-of-entries shape.
 func isValidationError(err error) bool {
 	var vErrs validator.ValidationErrors
-	return errors.As(err, &vErrs)
+	if errors.As(err, &vErrs) {
+		return true
+	}
+	_, ok := errors.AsType[ValidationIssues](err)
+	return ok
 }
 
 // categorizeError extracts the (code, type, message, details) tuple from any
 // internal error produced by parseInput. Used by the default error handler to
 // build either an ErrorEnvelope or a user-supplied DefaultErrorShape instance.
-func categorizeError(err error) errorCategory {
+// Validation messages are rendered by msg, in the request's locale.
+func categorizeError(err error, msg messageFunc) errorCategory {
 	if authErr, ok := errors.AsType[*AuthError](err); ok {
 		t := errTypeAuthN
 		if authErr.StatusCode == fiber.StatusForbidden {
@@ -206,7 +224,7 @@ func categorizeError(err error) errorCategory {
 	if errors.As(err, &vErrs) {
 		msgs := make([]string, 0, len(vErrs))
 		for _, fe := range vErrs {
-			msgs = append(msgs, translateValidatorTag(fe.Field(), fe.Tag(), fe.Param()))
+			msgs = append(msgs, msg(fe.Field(), fe.ActualTag(), fe.Param()))
 		}
 		head := msgs[0]
 		if len(msgs) > 1 {
@@ -219,6 +237,17 @@ func categorizeError(err error) errorCategory {
 			Details: strings.Join(msgs, "; "),
 		}
 	}
+	if bodyErr, ok := errors.AsType[*bodyRejectedError](err); ok {
+		return bodyErr.category()
+	}
+	if strictErr, ok := errors.AsType[*strictJSONError](err); ok {
+		return errorCategory{
+			Code:    statusParseError,
+			Type:    errTypeParse,
+			Message: strictErr.Error(),
+			Details: strictErr.Constraint,
+		}
+	}
 	return errorCategory{
 		Code:    statusParseError,
 		Type:    errTypeParse,
@@ -0,0 +1,113 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// errTypeGone is the entry type of the 410 served for a sunset operation when
+// Config.EnforceSunset is set.
+const errTypeGone = "gone"
+
+// isDeprecated reports whether an operation is deprecated, explicitly or by
+// declaring a deprecation date, a sunset date or a successor.
+func (op OpenAPIOptions) isDeprecated() bool {
+	return op.Deprecated || !op.DeprecatedSince.IsZero() || !op.Sunset.IsZero() || op.Successor != ""
+}
+
+// setDeprecationHeaders adds the Deprecation (RFC 9745), Sunset (RFC 8594)
+// and Link rel="successor-version" headers of a deprecated operation.
+func (o *OApiApp) setDeprecationHeaders(c fiber.Ctx, op *OpenAPIOperation) {
+	opts := op.Options
+	if opts.DeprecatedSince.IsZero() {
+		c.Set("Deprecation", "true")
+	} else {
+		c.Set("Deprecation", "@"+strconv.FormatInt(opts.DeprecatedSince.Unix(), 10))
+	}
+	if !opts.Sunset.IsZero() {
+		c.Set("Sunset", opts.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if opts.Successor != "" {
+		// The successor may live in another API version: URLFor searches them all.
+		target, err := o.URLFor(opts.Successor, currentPathParams(c, op.Path))
+		if err != nil {
+			slog.Warn("fiberoapi: successor link omitted",
+				"method", op.Method, "path", op.Path, "successor", opts.Successor, "error", err.Error())
+			return
+		}
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="successor-version"`, target))
+	}
+}
+
+// currentPathParams returns the path parameters of the request, so a
+// successor sharing them (e.g. /v1/users/:id -> /v2/users/:id) resolves to
+// the same resource.
+func currentPathParams(c fiber.Ctx, path string) map[string]string {
+	params := map[string]string{}
+	for _, p := range extractFiberPathParams(path) {
+		name := pathParamName(p)
+		if v := c.Params(name); v != "" {
+			params[name] = v
+		}
+	}
+	return params
+}
+
+// sunsetPassed reports whether a sunset operation must answer 410.
+func (o *OApiApp) sunsetPassed(opts OpenAPIOptions) bool {
+	return o.config.EnforceSunset && !opts.Sunset.IsZero() && !time.Now().Before(opts.Sunset)
+}
+
+func goneMessage(sunset time.Time) string {
+	return fmt.Sprintf("this operation was retired on %s", sunset.UTC().Format(time.RFC3339))
+}
+
+// serveGone writes the 410 of an operation past its sunset date.
+func (o *OApiApp) serveGone(c fiber.Ctx, opts OpenAPIOptions) error {
+	cat := errorCategory{
+		Code:    fiber.StatusGone,
+		Type:    errTypeGone,
+		Message: goneMessage(opts.Sunset),
+	}
+	if opts.Successor != "" {
+		cat.Details = "successor=" + opts.Successor
+	}
+	return o.sendError(c, fiber.StatusGone, cat, []ValidationErrorEntry{goneEntry(cat)})
+}
+
+func goneEntry(cat errorCategory) ValidationErrorEntry {
+	return ValidationErrorEntry{
+		Type:       cat.Type,
+		Code:       cat.Code,
+		Loc:        []any{},
+		Msg:        cat.Message,
+		Constraint: cat.Details,
+	}
+}
+
+// addDeprecationToSpec marks a deprecated operation in the spec: deprecated,
+// x-sunset (RFC 3339) and x-successor (operationId).
+func addDeprecationToSpec(operation map[string]interface{}, opts OpenAPIOptions) {
+	if !opts.isDeprecated() {
+		return
+	}
+	operation["deprecated"] = true
+	if !opts.Sunset.IsZero() {
+		operation["x-sunset"] = opts.Sunset.UTC().Format(time.RFC3339)
+	}
+	if opts.Successor != "" {
+		operation["x-successor"] = opts.Successor
+	}
+}
+
+// isDeprecatedField reports whether a struct field is tagged deprecated:"true".
+func isDeprecatedField(tag string) bool {
+	deprecated, _ := strconv.ParseBool(strings.TrimSpace(tag))
+	return deprecated
+}
@@ -0,0 +1,141 @@
+package fiberoapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deprecatedUserInput struct {
+	ID       string `uri:"id"`
+	Legacy   string `query:"legacy" deprecated:"true"`
+	Nickname string `json:"nickname" deprecated:"true"`
+	Name     string `json:"name"`
+}
+
+type deprecatedUserOutput struct {
+	ID string `json:"id"`
+}
+
+func updateDeprecatedUser(c fiber.Ctx, in deprecatedUserInput) (deprecatedUserOutput, error) {
+	return deprecatedUserOutput{ID: in.ID}, nil
+}
+
+func TestDeprecation_Headers(t *testing.T) {
+	sunset := time.Date(2099, 6, 30, 0, 0, 0, 0, time.UTC)
+	oapi := newTestApp(Config{EnforceSunset: true})
+	Put(oapi, "/v1/users/:id", updateDeprecatedUser, OpenAPIOptions{
+		OperationID:     "updateUserV1",
+		DeprecatedSince: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:          sunset,
+		Successor:       "updateUserV2",
+	})
+	Put(oapi, "/v2/users/:id", updateDeprecatedUser, OpenAPIOptions{OperationID: "updateUserV2"})
+	Get(oapi, "/legacy", func(c fiber.Ctx, in struct{}) (deprecatedUserOutput, error) {
+		return deprecatedUserOutput{}, nil
+	}, OpenAPIOptions{Deprecated: true})
+
+	resp := sendRequest(t, oapi, "PUT", "/v1/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "@1704067200", resp.Header.Get("Deprecation"))
+	assert.Equal(t, "Tue, 30 Jun 2099 00:00:00 GMT", resp.Header.Get("Sunset"))
+	assert.Equal(t, `</v2/users/42>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	resp = sendRequest(t, oapi, "GET", "/legacy", "")
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Empty(t, resp.Header.Get("Sunset"))
+
+	resp = sendRequest(t, oapi, "PUT", "/v2/users/42", "")
+	assert.Empty(t, resp.Header.Get("Deprecation"))
+}
+
+func TestDeprecation_SuccessorInAnotherVersion(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2"}, Default: "1"}})
+	Put(oapi, "/users/:id", updateDeprecatedUser, OpenAPIOptions{
+		OperationID: "updateUserV1",
+		Versions:    VersionRange{To: "1"},
+		Successor:   "updateUserV2",
+	})
+	Put(oapi, "/users/:id", updateDeprecatedUser, OpenAPIOptions{OperationID: "updateUserV2", Versions: VersionRange{From: "2"}})
+
+	resp := sendRequest(t, oapi, "PUT", "/v1/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, `</v2/users/42>; rel="successor-version"`, resp.Header.Get("Link"))
+}
+
+func TestDeprecation_GoneAfterSunset(t *testing.T) {
+	retired := func(cfg Config) *OApiApp {
+		oapi := newTestApp(cfg)
+		Put(oapi, "/v1/users/:id", updateDeprecatedUser, OpenAPIOptions{
+			OperationID: "updateUserV1",
+			Sunset:      time.Date(2020, 6, 30, 0, 0, 0, 0, time.UTC),
+			Successor:   "updateUserV2",
+		})
+		Put(oapi, "/v2/users/:id", updateDeprecatedUser, OpenAPIOptions{OperationID: "updateUserV2"})
+		return oapi
+	}
+
+	oapi := retired(Config{})
+	resp := sendRequest(t, oapi, "PUT", "/v1/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode, "sunset is advisory unless enforced")
+
+	oapi = retired(Config{EnforceSunset: true})
+	resp = sendRequest(t, oapi, "PUT", "/v1/users/42", "")
+	require.Equal(t, 410, resp.StatusCode)
+	assert.Equal(t, `</v2/users/42>; rel="successor-version"`, resp.Header.Get("Link"))
+
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "gone", env.Errors[0].Type)
+	assert.Equal(t, 410, env.Errors[0].Code)
+	assert.Equal(t, "this operation was retired on 2020-06-30T00:00:00Z", env.Errors[0].Msg)
+	assert.Equal(t, "successor=updateUserV2", env.Errors[0].Constraint)
+
+	oapi = retired(Config{EnforceSunset: true, DefaultErrorShape: &UniErr{}})
+	resp = sendRequest(t, oapi, "PUT", "/v1/users/42", "")
+	require.Equal(t, 410, resp.StatusCode)
+	var body UniErr
+	resp.decode(t, &body)
+	assert.Equal(t, "gone", body.Type)
+}
+
+func TestDeprecation_Spec(t *testing.T) {
+	oapi := newTestApp(Config{EnforceSunset: true})
+	Put(oapi, "/v1/users/:id", updateDeprecatedUser, OpenAPIOptions{
+		OperationID: "updateUserV1",
+		Sunset:      time.Date(2099, 6, 30, 0, 0, 0, 0, time.UTC),
+		Successor:   "updateUserV2",
+	})
+	Put(oapi, "/v2/users/:id", updateDeprecatedUser, OpenAPIOptions{OperationID: "updateUserV2"})
+	Get(oapi, "/legacy", func(c fiber.Ctx, in struct{}) (deprecatedUserOutput, error) {
+		return deprecatedUserOutput{}, nil
+	}, OpenAPIOptions{Deprecated: true})
+	spec := oapi.GenerateOpenAPISpec()
+	paths := spec["paths"].(map[string]interface{})
+
+	v1 := paths["/v1/users/{id}"].(map[string]interface{})["put"].(map[string]interface{})
+	assert.Equal(t, true, v1["deprecated"])
+	assert.Equal(t, "2099-06-30T00:00:00Z", v1["x-sunset"])
+	assert.Equal(t, "updateUserV2", v1["x-successor"])
+	assert.Contains(t, v1["responses"], "410")
+
+	for _, p := range v1["parameters"].([]map[string]interface{}) {
+		assert.Equal(t, p["name"] == "legacy", p["deprecated"] == true, p["name"])
+	}
+
+	v2 := paths["/v2/users/{id}"].(map[string]interface{})["put"].(map[string]interface{})
+	assert.NotContains(t, v2, "deprecated")
+	assert.NotContains(t, v2["responses"], "410")
+
+	legacy := paths["/legacy"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, true, legacy["deprecated"])
+	assert.NotContains(t, legacy, "x-sunset")
+
+	schema := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["deprecatedUserInput"].(map[string]interface{})
+	props := schema["properties"].(map[string]interface{})
+	assert.Equal(t, true, props["nickname"].(map[string]interface{})["deprecated"])
+	assert.NotContains(t, props["name"], "deprecated")
+}
@@ -0,0 +1,201 @@
+package fiberoapi
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// errorMapping is one MapError registration.
+type errorMapping struct {
+	sentinel error        // matched with errors.Is
+	typ      reflect.Type // matched with errors.As when sentinel is nil
+	status   int
+	errType  string
+}
+
+// MapError registers how an error returned by a handler (TError = error) is
+// turned into a response. target is either:
+//   - a sentinel error, matched anywhere in the wrap chain with errors.Is:
+//     oapi.MapError(ErrNotFound, 404, "not_found");
+//   - a nil pointer or a reflect.Type naming an error type, matched with
+//     errors.As: oapi.MapError((*QuotaError)(nil), 429, "quota_exceeded").
+//     A value type also matches its pointer and the other way round, and an
+//     interface type, e.g. (*Temporary)(nil), matches every error
+//     implementing it.
+//
+// Mappings are tried in registration order. A matched error is rendered in the
+// configured error shape (DefaultErrorShape, or an ErrorEnvelope entry) with
+// its Error() text as the message.
+func (o *OApiApp) MapError(target any, status int, errType string) {
+	m := errorMapping{status: status, errType: errType}
+	switch t := target.(type) {
+	case nil:
+		panic("fiberoapi: MapError target must not be nil")
+	case reflect.Type:
+		m.typ = t
+	default:
+		v := reflect.ValueOf(target)
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			m.typ = v.Type()
+			if m.typ.Elem().Kind() == reflect.Interface {
+				m.typ = m.typ.Elem()
+			}
+		} else if err, ok := target.(error); ok {
+			m.sentinel = err
+		} else {
+			panic("fiberoapi: MapError target must be an error, a nil pointer or a reflect.Type")
+		}
+	}
+	if m.typ != nil && m.typ.Kind() != reflect.Interface && !m.typ.Implements(errorType) && !reflect.PointerTo(m.typ).Implements(errorType) {
+		panic("fiberoapi: MapError type " + m.typ.String() + " does not implement error")
+	}
+	o.errorMappings = append(o.errorMappings, m)
+}
+
+// match reports whether err is covered by the mapping.
+func (m errorMapping) match(err error) bool {
+	if m.sentinel != nil {
+		return errors.Is(err, m.sentinel)
+	}
+	// errors.As accepts any interface, and concrete types implementing error.
+	if (m.typ.Kind() == reflect.Interface || m.typ.Implements(errorType)) && errors.As(err, reflect.New(m.typ).Interface()) {
+		return true
+	}
+	// T also matches *T and the other way round.
+	var alt reflect.Type
+	switch {
+	case m.typ.Kind() == reflect.Ptr:
+		alt = m.typ.Elem()
+	case m.typ.Kind() != reflect.Interface:
+		alt = reflect.PointerTo(m.typ)
+	}
+	return alt != nil && alt.Implements(errorType) && errors.As(err, reflect.New(alt).Interface())
+}
+
+// errorChain yields err and every error it wraps, depth first, the way
+// errors.Is and errors.As walk it.
+func errorChain(err error) func(yield func(error) bool) {
+	return func(yield func(error) bool) {
+		var walk func(error) bool
+		walk = func(e error) bool {
+			if e == nil {
+				return true
+			}
+			if !yield(e) {
+				return false
+			}
+			switch u := e.(type) {
+			case interface{ Unwrap() error }:
+				return walk(u.Unwrap())
+			case interface{ Unwrap() []error }:
+				for _, inner := range u.Unwrap() {
+					if !walk(inner) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		walk(err)
+	}
+}
+
+// handleCustomError writes the non-zero TError returned by a handler. Status
+// codes resolve exactly as in the spec (extractErrorStatusCode: HTTPStatus()
+// first, then StatusCode / Code fields). Errors are resolved in this order:
+//  1. a MapError registration matching the chain → configured error shape;
+//  2. a *fiber.Error in the chain → configured error shape with its code;
+//  3. an error in the chain carrying a body (a struct with exported fields,
+//     typically a declared error) → that value with its own status;
+//  4. anything else → 500 internal_error, without exposing the error text.
+//
+// Non-error TError values are sent as-is with their resolved status.
+func (o *OApiApp) handleCustomError(c fiber.Ctx, customErr any) error {
+	err, isErr := customErr.(error)
+	if !isErr {
+		return sendErrorBody(c, extractErrorStatusCode(customErr), customErr)
+	}
+
+	for _, m := range o.errorMappings {
+		if m.match(err) {
+			return o.writeErrorCategory(c, errorCategory{Code: m.status, Type: m.errType, Message: err.Error()})
+		}
+	}
+	if fe, ok := errors.AsType[*fiber.Error](err); ok {
+		return o.writeErrorCategory(c, errorCategory{Code: fe.Code, Type: statusErrorType(fe.Code), Message: fe.Message})
+	}
+	for e := range errorChain(err) {
+		if hasErrorBody(e) {
+			return sendErrorBody(c, extractErrorStatusCode(e), e)
+		}
+	}
+	// Opaque errors (no body) exposing HTTPStatus(), possibly wrapped.
+	for e := range errorChain(err) {
+		if r, ok := e.(HTTPStatusError); ok && r.HTTPStatus() > 0 {
+			return o.writeErrorCategory(c, errorCategory{Code: r.HTTPStatus(), Type: statusErrorType(r.HTTPStatus()), Message: err.Error()})
+		}
+	}
+	return o.writeErrorCategory(c, internalErrorCategory())
+}
+
+// sendErrorBody sends a declared error value as the response body.
+func sendErrorBody(c fiber.Ctx, status int, body any) error {
+	if v := reflect.ValueOf(body); v.Kind() == reflect.Ptr && v.IsNil() {
+		return c.Status(500).JSON(fiber.Map{"error": "Internal server error"})
+	}
+	if err := c.Status(status).JSON(body); err != nil {
+		if fallbackErr := c.Status(500).JSON(fiber.Map{"error": "Failed to serialize error response"}); fallbackErr != nil {
+			// Both serializations failed, return original error to Fiber
+			return err
+		}
+		return nil
+	}
+	return nil
+}
+
+// hasErrorBody reports whether an error value serializes to something useful,
+// i.e. is a struct with at least one exported field. errors.New values and
+// fmt.Errorf wrappers do not, and would be sent as {}.
+func hasErrorBody(v any) bool {
+	t := dereferenceType(reflect.TypeOf(v))
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// statusErrorType derives an entry type from a status code: 404 → not_found,
+// 409 → conflict, 503 → service_unavailable.
+func statusErrorType(status int) string {
+	if status == fiber.StatusInternalServerError {
+		return errTypeInternal
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "http_error"
+	}
+	return strings.ToLower(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
+// writeErrorCategory renders an error in the configured format with a single
+// entry built from cat.
+func (o *OApiApp) writeErrorCategory(c fiber.Ctx, cat errorCategory) error {
+	return o.sendError(c, cat.Code, cat, []ValidationErrorEntry{{
+		Type:       cat.Type,
+		Code:       cat.Code,
+		Loc:        []any{},
+		Msg:        cat.Message,
+		Constraint: cat.Details,
+	}})
+}
@@ -0,0 +1,166 @@
+package fiberoapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errMapNotFound = errors.New("item not found")
+
+type mapQuotaError struct{ limit int }
+
+func (e *mapQuotaError) Error() string { return fmt.Sprintf("quota of %d exceeded", e.limit) }
+
+// mapConflictError has a body and a status of its own.
+type mapConflictError struct {
+	Reason string `json:"reason"`
+}
+
+func (e *mapConflictError) Error() string   { return e.Reason }
+func (e *mapConflictError) HTTPStatus() int { return 409 }
+
+// mapTeapotError has no body but declares its status.
+type mapTeapotError struct{}
+
+func (mapTeapotError) Error() string   { return "short and stout" }
+func (mapTeapotError) HTTPStatus() int { return 418 }
+
+// mapStatusBody declares its status both through HTTPStatus and a Code field;
+// the method wins, as in the spec.
+type mapStatusBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (mapStatusBody) HTTPStatus() int { return 451 }
+
+func TestMapError_Envelope(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		status int
+		typ    string
+		msg    string
+	}{
+		{"sentinel", errMapNotFound, 404, "not_found", "item not found"},
+		{"wrapped sentinel", fmt.Errorf("loading 42: %w", errMapNotFound), 404, "not_found", "loading 42: item not found"},
+		{"joined sentinel", errors.Join(errors.New("cache miss"), errMapNotFound), 404, "not_found", "cache miss\nitem not found"},
+		{"wrapped type", fmt.Errorf("upload: %w", &mapQuotaError{limit: 3}), 429, "quota_exceeded", "upload: quota of 3 exceeded"},
+		{"fiber error", fmt.Errorf("proxy: %w", fiber.NewError(503, "upstream down")), 503, "service_unavailable", "upstream down"},
+		{"opaque with status", fmt.Errorf("brew: %w", mapTeapotError{}), 418, "im_a_teapot", "brew: short and stout"},
+		{"unknown", errors.New("pq: connection refused"), 500, "internal_error", "internal server error"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oapi := newTestApp()
+			oapi.MapError(errMapNotFound, 404, "not_found")
+			oapi.MapError((*mapQuotaError)(nil), 429, "quota_exceeded")
+			Get(oapi, "/items", func(c fiber.Ctx, in struct{}) (struct{}, error) {
+				return struct{}{}, tc.err
+			}, OpenAPIOptions{})
+
+			resp := sendRequest(t, oapi, "GET", "/items", "")
+			require.Equal(t, tc.status, resp.StatusCode, "%s", resp.body)
+			env := resp.envelope(t)
+			require.Len(t, env.Errors, 1)
+			assert.Equal(t, tc.typ, env.Errors[0].Type)
+			assert.Equal(t, tc.status, env.Errors[0].Code)
+			assert.Equal(t, tc.msg, env.Errors[0].Msg)
+		})
+	}
+}
+
+func TestMapError_DeclaredBodies(t *testing.T) {
+	oapi := newTestApp()
+	Get(oapi, "/items", func(c fiber.Ctx, in struct{}) (struct{}, error) {
+		return struct{}{}, fmt.Errorf("save: %w", &mapConflictError{Reason: "locked"})
+	}, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "GET", "/items", "")
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.JSONEq(t, `{"reason":"locked"}`, string(resp.body), "the wrapped declared error is sent as-is")
+
+	Get(oapi, "/legal", func(c fiber.Ctx, in struct{}) (struct{}, *mapStatusBody) {
+		return struct{}{}, &mapStatusBody{Code: 400, Message: "blocked"}
+	}, OpenAPIOptions{Errors: []any{mapStatusBody{}}})
+	resp = sendRequest(t, oapi, "GET", "/legal", "")
+	assert.Equal(t, 451, resp.StatusCode, "runtime status matches the spec's")
+
+	spec := oapi.GenerateOpenAPISpec()
+	responses := spec["paths"].(map[string]interface{})["/legal"].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})
+	assert.Contains(t, responses, "451")
+}
+
+func TestMapError_DefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{DefaultErrorShape: &UniErr{}})
+	oapi.MapError(errMapNotFound, 404, "not_found")
+	Get(oapi, "/items", func(c fiber.Ctx, in struct{}) (struct{}, error) {
+		return struct{}{}, fmt.Errorf("x: %w", errMapNotFound)
+	}, OpenAPIOptions{})
+
+	resp := sendRequest(t, oapi, "GET", "/items", "")
+	require.Equal(t, 404, resp.StatusCode)
+	var got UniErr
+	resp.decode(t, &got)
+	assert.Equal(t, "not_found", got.Type)
+	assert.Equal(t, "x: item not found", got.Message)
+}
+
+func TestMapError_OrderAndTargets(t *testing.T) {
+	oapi := newTestApp()
+	oapi.MapError(reflect.TypeOf(&mapQuotaError{}), 402, "payment_required")
+	oapi.MapError((*mapQuotaError)(nil), 429, "quota_exceeded")
+	Get(oapi, "/items", func(c fiber.Ctx, in struct{}) (struct{}, error) {
+		return struct{}{}, &mapQuotaError{limit: 1}
+	}, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "GET", "/items", "")
+	assert.Equal(t, 402, resp.StatusCode, "first registration wins")
+
+	assert.Panics(t, func() { oapi.MapError(nil, 400, "x") })
+	assert.Panics(t, func() { oapi.MapError((*mapStatusBody)(nil), 400, "x") }, "not an error type")
+}
+
+// mapTemporary is implemented by errors worth retrying.
+type mapTemporary interface{ Temporary() bool }
+
+type mapTimeoutError struct{}
+
+func (mapTimeoutError) Error() string   { return "timed out" }
+func (mapTimeoutError) Temporary() bool { return true }
+
+func TestMapError_InterfaceAndPointerTargets(t *testing.T) {
+	oapi := newTestApp()
+	oapi.MapError((*mapTemporary)(nil), 503, "unavailable")
+	oapi.MapError((*mapTeapotError)(nil), 418, "teapot")
+	oapi.MapError(reflect.TypeOf(mapQuotaError{}), 429, "quota_exceeded")
+	Get(oapi, "/items/:kind", func(c fiber.Ctx, in struct {
+		Kind string `uri:"kind"`
+	}) (struct{}, error) {
+		switch in.Kind {
+		case "timeout":
+			return struct{}{}, fmt.Errorf("fetch: %w", mapTimeoutError{})
+		case "teapot":
+			return struct{}{}, fmt.Errorf("brew: %w", mapTeapotError{})
+		}
+		return struct{}{}, &mapQuotaError{limit: 1}
+	}, OpenAPIOptions{})
+
+	cases := []struct {
+		kind, typ string
+		status    int
+	}{
+		{"timeout", "unavailable", 503},  // interface target
+		{"teapot", "teapot", 418},        // *T target, T returned
+		{"quota", "quota_exceeded", 429}, // T target, *T returned
+	}
+	for _, tc := range cases {
+		resp := sendRequest(t, oapi, "GET", "/items/"+tc.kind, "")
+		require.Equal(t, tc.status, resp.StatusCode, tc.kind)
+		assert.Equal(t, tc.typ, resp.envelope(t).Errors[0].Type, tc.kind)
+	}
+}
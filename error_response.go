@@ -196,56 +196,23 @@ func wrapJSONTypeError(err error) error {
 	return &friendlyJSONError{msg: msg, ute: ute}
 }
 
-// translateValidatorTag turns a validator tag + parameter into a human-readable
-// message. Covers the most common tags from go-playground/validator; anything
-// unknown falls back to a generic message that still exposes the tag name so the
-// client side stays informative.
+// translateValidatorTag turns a validator tag + parameter into the English
+// message of the built-in catalogue. Covers the most common tags from
+// go-playground/validator; anything unknown falls back to a generic message
+// that still exposes the tag name so the client side stays informative.
+// Request-facing messages go through the app's catalogues instead (see
+// RegisterMessages).
 func translateValidatorTag(field, tag, param string) string {
-	switch tag {
-	case "required":
-		return fmt.Sprintf("field '%s' is required", field)
-	case "min":
-		return fmt.Sprintf("field '%s' must be at least %s", field, param)
-	case "max":
-		return fmt.Sprintf("field '%s' must be at most %s", field, param)
-	case "len":
-		return fmt.Sprintf("field '%s' must be exactly %s", field, param)
-	case "email":
-		return fmt.Sprintf("field '%s' must be a valid email address", field)
-	case "url":
-		return fmt.Sprintf("field '%s' must be a valid URL", field)
-	case "uuid", "uuid4":
-		return fmt.Sprintf("field '%s' must be a valid UUID", field)
-	case "alphanum":
-		return fmt.Sprintf("field '%s' must contain only alphanumeric characters", field)
-	case "alpha":
-		return fmt.Sprintf("field '%s' must contain only alphabetic characters", field)
-	case "numeric":
-		return fmt.Sprintf("field '%s' must be numeric", field)
-	case "oneof":
-		return fmt.Sprintf("field '%s' must be one of: %s", field, param)
-	case "gte":
-		return fmt.Sprintf("field '%s' must be greater than or equal to %s", field, param)
-	case "lte":
-		return fmt.Sprintf("field '%s' must be less than or equal to %s", field, param)
-	case "gt":
-		return fmt.Sprintf("field '%s' must be greater than %s", field, param)
-	case "lt":
-		return fmt.Sprintf("field '%s' must be less than %s", field, param)
-	default:
-		if param != "" {
-			return fmt.Sprintf("field '%s' failed validation: %s=%s", field, tag, param)
-		}
-		return fmt.Sprintf("field '%s' failed validation: %s", field, tag)
-	}
+	return renderMessage([]MessageCatalog{builtinCatalogs[defaultLocale]}, field, tag, param)
 }
 
 // buildEnvelope produces an ErrorEnvelope from any error returned by parseInput.
 // The status code carried in the envelope entries (and intended to be set on the
 // response) is returned alongside so the handler can call c.Status() once.
-func buildEnvelope(c fiber.Ctx, cfg Config, inputType reflect.Type, err error) (ErrorEnvelope, int) {
+// Validation messages are rendered by msg, in the request's locale.
+func buildEnvelope(c fiber.Ctx, cfg Config, msg messageFunc, inputType reflect.Type, err error) (ErrorEnvelope, int) {
 	resolver := resolverFor(inputType)
-	ctx := ResponseContext{ResponseID: sanitizeRequestID(c.Get(requestIDHeader))}
+	ctx := responseContext(c)
 
 	// AuthError — single entry, status from the error itself.
 	if authErr, ok := errors.AsType[*AuthError](err); ok {
@@ -301,7 +268,8 @@ func buildEnvelope(c fiber.Ctx, cfg Config, inputType reflect.Type, err error) (
 		return ErrorEnvelope{Errors: []ValidationErrorEntry{entry}, ResponseContext: ctx}, statusParseError
 	}
 
-	// validator.ValidationErrors — one entry per failing field, 422.
+	// validator.ValidationErrors — one entry per failing field, 422. Entries
+	// name the failing tag itself, not the alias that contained it.
 	var vErrs validator.ValidationErrors
 	if errors.As(err, &vErrs) {
 		entries := make([]ValidationErrorEntry, 0, len(vErrs))
@@ -315,8 +283,8 @@ func buildEnvelope(c fiber.Ctx, cfg Config, inputType reflect.Type, err error) (
 				Code:       statusValidationError,
 				Loc:        loc,
 				Field:      leaf,
-				Msg:        translateValidatorTag(leaf, fe.Tag(), fe.Param()),
-				Constraint: constraintString(fe.Tag(), fe.Param()),
+				Msg:        msg(leaf, fe.ActualTag(), fe.Param()),
+				Constraint: constraintString(fe.ActualTag(), fe.Param()),
 			}
 			if cfg.IncludeInvalidValueInErrors {
 				entry.Value = fe.Value()
@@ -326,6 +294,31 @@ func buildEnvelope(c fiber.Ctx, cfg Config, inputType reflect.Type, err error) (
 		return ErrorEnvelope{Errors: entries, ResponseContext: ctx}, statusValidationError
 	}
 
+	// Issues reported by the input's Validate method — 422 like tag failures.
+	if issues, ok := errors.AsType[ValidationIssues](err); ok {
+		return ErrorEnvelope{Errors: issueEntries(msg, resolver, issues), ResponseContext: ctx}, statusValidationError
+	}
+
+	// Body size / media type rejected before decoding — 413 / 415.
+	if bodyErr, ok := errors.AsType[*bodyRejectedError](err); ok {
+		return ErrorEnvelope{Errors: []ValidationErrorEntry{bodyErr.entry()}, ResponseContext: ctx}, bodyErr.Status
+	}
+
+	// Strict JSON — unknown or duplicate key, loc points at the key.
+	if strictErr, ok := errors.AsType[*strictJSONError](err); ok {
+		return ErrorEnvelope{
+			Errors: []ValidationErrorEntry{{
+				Type:       errTypeParse,
+				Code:       statusParseError,
+				Loc:        strictErr.Loc,
+				Field:      strictErr.Key,
+				Msg:        strictErr.Error(),
+				Constraint: strictErr.Constraint,
+			}},
+			ResponseContext: ctx,
+		}, statusParseError
+	}
+
 	// Anything else — generic parse error.
 	return ErrorEnvelope{
 		Errors: []ValidationErrorEntry{{
@@ -345,24 +338,36 @@ func constraintString(tag, param string) string {
 	return tag + "=" + param
 }
 
-// exampleEnvelope returns a representative ErrorEnvelope used as the OpenAPI
+// exampleResponseID is the response_id shown by every error example of the
+// spec.
+const exampleResponseID = "bf0e9029-576b-42e8-84f9-ad0622972f50"
+
+// exampleEnvelope wraps the entries of a spec example in an ErrorEnvelope.
+func exampleEnvelope(entries ...ValidationErrorEntry) ErrorEnvelope {
+	return ErrorEnvelope{
+		Errors:          entries,
+		ResponseContext: ResponseContext{ResponseID: exampleResponseID},
+	}
+}
+
+// exampleValidationEnvelope returns a representative ErrorEnvelope used as the OpenAPI
 // example for the 422 response. It is deliberately compact but realistic enough
 // to show the shape to consumers reading the spec.
-func exampleValidationEnvelope() ErrorEnvelope {
+func exampleValidationEnvelope(msg messageFunc) ErrorEnvelope {
 	return ErrorEnvelope{
 		Errors: []ValidationErrorEntry{{
 			Type: errTypeValidation,
 			Code: statusValidationError,
 			Loc:  []any{"body", "workspaceId"},
-			// Routing through translateValidatorTag (the same function the
-			// runtime calls) keeps the spec example wording perfectly aligned
-			// with what clients will receive — no risk of drift if either side
-			// changes the wording later.
+			// Routing through the message catalogue (the same one the runtime
+			// uses) keeps the spec example wording perfectly aligned with what
+			// clients will receive — no risk of drift if either side changes
+			// the wording later.
 			Field:      "workspaceId",
-			Msg:        translateValidatorTag("workspaceId", "min", "11"),
+			Msg:        msg("workspaceId", "min", "11"),
 			Constraint: "min=11",
 		}},
-		ResponseContext: ResponseContext{ResponseID: "bf0e9029-576b-42e8-84f9-ad0622972f50"},
+		ResponseContext: ResponseContext{ResponseID: exampleResponseID},
 	}
 }
 
@@ -381,7 +386,7 @@ func NotFoundEnvelope(c fiber.Ctx) ErrorEnvelope {
 			Field: path,
 			Msg:   fmt.Sprintf("no route matches %s %s", method, path),
 		}},
-		ResponseContext: ResponseContext{ResponseID: sanitizeRequestID(c.Get(requestIDHeader))},
+		ResponseContext: responseContext(c),
 	}
 }
 
@@ -400,7 +405,7 @@ func methodNotAllowedEnvelope(c fiber.Ctx, allowed []string) ErrorEnvelope {
 			Msg:        fmt.Sprintf("method %s not allowed on %s; allowed: %s", method, path, strings.Join(allowed, ", ")),
 			Constraint: strings.Join(allowed, ","),
 		}},
-		ResponseContext: ResponseContext{ResponseID: sanitizeRequestID(c.Get(requestIDHeader))},
+		ResponseContext: responseContext(c),
 	}
 }
 
@@ -419,36 +424,30 @@ func (o *OApiApp) defaultNotFoundHandler() fiber.Handler {
 		if c.Method() == fiber.MethodOptions {
 			return c.Next()
 		}
-		shape := o.config.DefaultErrorShape
 		// 405: the path exists on another method.
-		if allowed := o.allowedMethodsFor(c.Path()); len(allowed) > 0 {
+		if allowed := o.allowedMethodsFor(c.Path(), o.notFoundVersion(c)); len(allowed) > 0 {
 			c.Set(fiber.HeaderAllow, strings.Join(allowed, ", "))
-			if shape != nil {
-				cat := errorCategory{
-					Code:    fiber.StatusMethodNotAllowed,
-					Type:    "method_not_allowed",
-					Message: fmt.Sprintf("method %s not allowed on %s", c.Method(), sanitizePath(c.Path())),
-					Details: strings.Join(allowed, ", "),
-				}
-				return c.Status(fiber.StatusMethodNotAllowed).JSON(materializeError(shape, cat))
-			}
-			return c.Status(fiber.StatusMethodNotAllowed).JSON(methodNotAllowedEnvelope(c, allowed))
-		}
-		if shape != nil {
 			cat := errorCategory{
-				Code:    fiber.StatusNotFound,
-				Type:    errTypeNotFound,
-				Message: fmt.Sprintf("no route matches %s %s", c.Method(), sanitizePath(c.Path())),
+				Code:    fiber.StatusMethodNotAllowed,
+				Type:    "method_not_allowed",
+				Message: fmt.Sprintf("method %s not allowed on %s", c.Method(), sanitizePath(c.Path())),
+				Details: strings.Join(allowed, ", "),
 			}
-			return c.Status(fiber.StatusNotFound).JSON(materializeError(shape, cat))
+			return o.sendError(c, fiber.StatusMethodNotAllowed, cat, methodNotAllowedEnvelope(c, allowed).Errors)
 		}
-		return c.Status(fiber.StatusNotFound).JSON(NotFoundEnvelope(c))
+		cat := errorCategory{
+			Code:    fiber.StatusNotFound,
+			Type:    errTypeNotFound,
+			Message: fmt.Sprintf("no route matches %s %s", c.Method(), sanitizePath(c.Path())),
+		}
+		return o.sendError(c, fiber.StatusNotFound, cat, NotFoundEnvelope(c).Errors)
 	}
 }
 
 // allowedMethodsFor walks the registered operations and returns the HTTP
 // methods that match the requested path (Fiber-style :param patterns supported).
-func (o *OApiApp) allowedMethodsFor(path string) []string {
+// A non-empty version ignores the operations of other API versions.
+func (o *OApiApp) allowedMethodsFor(path, version string) []string {
 	seen := map[string]struct{}{}
 	var allowed []string
 	for _, op := range o.operations {
@@ -458,6 +457,9 @@ func (o *OApiApp) allowedMethodsFor(path string) []string {
 		if !matchFiberPath(op.Path, path) {
 			continue
 		}
+		if version != "" && op.Version != "" && op.Version != version {
+			continue
+		}
 		if _, dup := seen[op.Method]; dup {
 			continue
 		}
@@ -552,7 +554,7 @@ func exampleNotFoundEnvelope() ErrorEnvelope {
 			Field: "/users/42",
 			Msg:   "no route matches GET /users/42",
 		}},
-		ResponseContext: ResponseContext{ResponseID: "bf0e9029-576b-42e8-84f9-ad0622972f50"},
+		ResponseContext: ResponseContext{ResponseID: exampleResponseID},
 	}
 }
 
@@ -566,6 +568,6 @@ func exampleParseEnvelope() ErrorEnvelope {
 			Msg:        fmt.Sprintf(typeMismatchMsgFmt, "age", "int", "string"),
 			Constraint: "int",
 		}},
-		ResponseContext: ResponseContext{ResponseID: "bf0e9029-576b-42e8-84f9-ad0622972f50"},
+		ResponseContext: ResponseContext{ResponseID: exampleResponseID},
 	}
 }
@@ -141,12 +141,68 @@ func New(app *fiber.App, config ...Config) *OApiApp {
 		if provided.IncludeInvalidValueInErrors {
 			cfg.IncludeInvalidValueInErrors = true
 		}
+		if provided.ResponseValidation != ResponseValidationOff {
+			cfg.ResponseValidation = provided.ResponseValidation
+		}
+		if provided.ResponseViolationHandler != nil {
+			cfg.ResponseViolationHandler = provided.ResponseViolationHandler
+		}
+		if provided.Mock {
+			cfg.Mock = true
+		}
+		if provided.StrictJSON {
+			cfg.StrictJSON = true
+		}
+		if provided.EnforceSunset {
+			cfg.EnforceSunset = true
+		}
+		if provided.Versioning != nil {
+			cfg.Versioning = provided.Versioning
+		}
+		if provided.RecoverPanics {
+			cfg.RecoverPanics = true
+		}
+		if provided.PanicHandler != nil {
+			cfg.PanicHandler = provided.PanicHandler
+		}
+		if provided.ProblemDetails {
+			cfg.ProblemDetails = true
+		}
+		if provided.ProblemTypeBaseURI != "" {
+			cfg.ProblemTypeBaseURI = provided.ProblemTypeBaseURI
+		}
+		if provided.Localization != nil {
+			cfg.Localization = provided.Localization
+		}
+		if provided.Hooks != nil {
+			cfg.Hooks = provided.Hooks
+		}
+		if provided.Metrics != nil {
+			cfg.Metrics = provided.Metrics
+		}
+		if provided.RateLimitStore != nil {
+			cfg.RateLimitStore = provided.RateLimitStore
+		}
+		if provided.IdempotencyStore != nil {
+			cfg.IdempotencyStore = provided.IdempotencyStore
+		}
 	}
 
 	oapi := &OApiApp{
-		f:          app,
-		operations: make([]OpenAPIOperation, 0),
-		config:     cfg,
+		f:           app,
+		operations:  make([]OpenAPIOperation, 0),
+		config:      cfg,
+		catalogs:    newMessageCatalogs(cfg.Localization),
+		validation:  newValidationRules(),
+		metrics:     newMetricsCollector(cfg.Metrics),
+		rateLimits:  cfg.RateLimitStore,
+		idempotency: cfg.IdempotencyStore,
+	}
+	if oapi.rateLimits == nil {
+		oapi.rateLimits = NewMemoryRateLimitStore()
+	}
+	if oapi.idempotency == nil {
+		oapi.idempotency = NewMemoryIdempotencyStore()
 	}
 
 	// Automatically setup documentation routes if enabled
@@ -154,6 +210,14 @@ func New(app *fiber.App, config ...Config) *OApiApp {
 		oapi.setupDocsRoutes()
 	}
 
+	if m := cfg.Metrics; m != nil && m.Path != "-" {
+		path := m.Path
+		if path == "" {
+			path = "/metrics"
+		}
+		app.Get(path, oapi.MetricsHandler())
+	}
+
 	return oapi
 }
 
@@ -188,6 +252,29 @@ func (o *OApiApp) setupDocsRoutes() {
 			return c.Redirect().To(o.Config().OpenAPIDocsPath)
 		})
 	}
+
+	// One spec and docs page per API version, under the version path prefix.
+	// The unprefixed routes above describe the default version.
+	if v := o.config.Versioning; v != nil {
+		for _, version := range v.Versions {
+			jsonPath := v.versionedDocsPath(o.config.OpenAPIJSONPath, version)
+			o.f.Get(jsonPath, func(c fiber.Ctx) error {
+				return c.JSON(o.ForVersion(version).GenerateOpenAPISpec())
+			})
+			o.f.Get(v.versionedDocsPath(o.config.OpenAPIYamlPath, version), func(c fiber.Ctx) error {
+				spec, err := o.ForVersion(version).GenerateOpenAPISpecYAML()
+				if err != nil {
+					return err
+				}
+				c.Set("Content-Type", "application/yaml")
+				return c.SendString(spec)
+			})
+			o.f.Get(v.versionedDocsPath(o.config.OpenAPIDocsPath, version), func(c fiber.Ctx) error {
+				c.Set("Content-Type", "text/html")
+				return c.SendString(generateRedocHTML(jsonPath, "API Documentation "+version))
+			})
+		}
+	}
 }
 
 // GetOperations returns all registered operations (useful for testing and documentation generation)
@@ -196,7 +283,11 @@ func (o *OApiApp) GetOperations() []OpenAPIOperation {
 }
 
 // GenerateOpenAPISpec generates a complete OpenAPI 3.0 specification
+// With Config.Versioning it describes the default version; see ForVersion.
 func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
+	if scope := o.specScope(); scope != o {
+		return scope.GenerateOpenAPISpec()
+	}
 	spec := map[string]interface{}{
 		"openapi": "3.0.0",
 		"info": map[string]interface{}{
@@ -208,6 +299,12 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 		"components": make(map[string]interface{}),
 	}
 
+	// info.version stays the document version; the API version of a
+	// versioned spec goes in an extension.
+	if o.specVersion != "" {
+		spec["info"].(map[string]interface{})["x-api-version"] = o.specVersion
+	}
+
 	paths := spec["paths"].(map[string]interface{})
 	components := spec["components"].(map[string]interface{})
 	schemas := make(map[string]interface{})
@@ -223,59 +320,11 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 		spec["security"] = o.config.DefaultSecurity
 	}
 
-	// First pass: collect all types that need schemas
-	allTypes := make(map[string]reflect.Type)
-
-	for _, op := range o.operations {
-		// Hidden operations contribute neither to the spec paths nor to the
-		// schemas index, so a type only ever referenced by a hidden route does
-		// not leak as a public component.
-		if op.Options.Hidden {
-			continue
-		}
-		if op.InputType != nil {
-			collectAllTypes(op.InputType, allTypes)
-		}
-		if op.OutputType != nil {
-			collectAllTypes(op.OutputType, allTypes)
-		}
-		if op.ErrorType != nil && !isEmptyStruct(op.ErrorType) {
-			collectAllTypes(op.ErrorType, allTypes)
-		}
-		// Each declared custom error contributes its own type to components.schemas
-		// so multiple error responses sharing a struct ($ref via the same name)
-		// stay deduplicated.
-		for _, errInst := range op.Options.Errors {
-			if errInst == nil {
-				continue
-			}
-			collectAllTypes(reflect.TypeOf(errInst), allTypes)
-		}
-	}
-
-	// When the user opted into a unified shape via Config.DefaultErrorShape, the
-	// per-operation responses below reference it by $ref. Make sure its schema
-	// (and any nested types) is collected so we never emit dangling references.
-	if o.config.DefaultErrorShape != nil {
-		collectAllTypes(reflect.TypeOf(o.config.DefaultErrorShape), allTypes)
-	}
-
-	// Second pass: generate all schemas
-	for typeName, typeInfo := range allTypes {
-		schemas[typeName] = generateSchema(typeInfo)
-	}
-
-	// Always expose the default error envelope shape so every route can $ref it.
-	collectAllTypes(reflect.TypeOf(ErrorEnvelope{}), allTypes)
-	if _, ok := schemas["ErrorEnvelope"]; !ok {
-		schemas["ErrorEnvelope"] = generateSchema(reflect.TypeOf(ErrorEnvelope{}))
-	}
-	if _, ok := schemas["ValidationErrorEntry"]; !ok {
-		schemas["ValidationErrorEntry"] = generateSchema(reflect.TypeOf(ValidationErrorEntry{}))
-	}
-	if _, ok := schemas["ResponseContext"]; !ok {
-		schemas["ResponseContext"] = generateSchema(reflect.TypeOf(ResponseContext{}))
+	// Generate a schema for every collected type
+	for typeName, typeInfo := range o.collectSpecTypes() {
+		schemas[typeName] = generateSchema(typeInfo, o.validation)
 	}
+	o.markClosedSchemas(schemas)
 
 	for _, op := range o.operations {
 		// Skip hidden operations entirely — the route still serves traffic,
@@ -308,6 +357,11 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 		if len(op.Options.Tags) > 0 {
 			enhancedOptions["tags"] = op.Options.Tags
 		}
+		if op.Options.Audience != "" {
+			enhancedOptions["x-audience"] = op.Options.Audience
+		}
+		addDeprecationToSpec(enhancedOptions, op.Options)
+		addRateLimitToSpec(enhancedOptions, op.Options)
 
 		// Auto-generate parameters from struct tags and merge with manual parameters
 		autoParameters := []map[string]interface{}{}
@@ -321,6 +375,22 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			allParameters = mergeParameters(autoParameters, op.Options.Parameters)
 		}
 
+		// Header-selected versions are documented as a parameter pinned to
+		// the version this spec describes.
+		if v := o.config.Versioning; v != nil && op.Version != "" && v.Strategy == VersionByHeader {
+			allParameters = append(allParameters, map[string]interface{}{
+				"name":        v.header(),
+				"in":          "header",
+				"required":    false,
+				"description": fmt.Sprintf("API version (default: %s)", v.defaultVersion()),
+				"schema":      map[string]interface{}{"type": "string", "enum": []string{op.Version}},
+			})
+		}
+
+		if op.Options.Idempotency != nil {
+			allParameters = append(allParameters, idempotencySpecParameter(op.Options.Idempotency))
+		}
+
 		if len(allParameters) > 0 {
 			enhancedOptions["parameters"] = allParameters
 		}
@@ -363,7 +433,10 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 				var schemaRef map[string]interface{}
 
 				if shouldInlineOperationSchema(inputType) {
-					schemaRef = generateSchema(inputType)
+					schemaRef = generateSchema(inputType, o.validation)
+					if inputType.Kind() == reflect.Struct && strictJSONEnabled(o.config, &op.Options) {
+						schemaRef["additionalProperties"] = false
+					}
 				} else {
 					inputSchemaName := getTypeName(inputType)
 					schemaRef = map[string]interface{}{
@@ -371,14 +444,20 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 					}
 				}
 
-				enhancedOptions["requestBody"] = map[string]interface{}{
+				content := make(map[string]interface{})
+				for _, mediaType := range requestMediaTypes(op.Options) {
+					content[mediaType] = map[string]interface{}{
+						"schema": schemaRef,
+					}
+				}
+				requestBody := map[string]interface{}{
 					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": schemaRef,
-						},
-					},
+					"content":  content,
+				}
+				if op.Options.MaxBodySize > 0 {
+					requestBody["x-max-body-size"] = op.Options.MaxBodySize
 				}
+				enhancedOptions["requestBody"] = requestBody
 			}
 		}
 
@@ -392,7 +471,7 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			var schemaRef map[string]interface{}
 
 			if shouldInlineOperationSchema(outputType) {
-				schemaRef = generateSchema(outputType)
+				schemaRef = generateSchema(outputType, o.validation)
 			} else {
 				outputSchemaName := getTypeName(outputType)
 				schemaRef = map[string]interface{}{
@@ -400,12 +479,16 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 				}
 			}
 
+			mediaType := map[string]interface{}{
+				"schema": schemaRef,
+			}
+			if op.Options.Example != nil {
+				mediaType["example"] = op.Options.Example
+			}
 			responses["200"] = map[string]interface{}{
 				"description": "Successful response",
 				"content": map[string]interface{}{
-					"application/json": map[string]interface{}{
-						"schema": schemaRef,
-					},
+					"application/json": mediaType,
 				},
 			}
 		}
@@ -436,7 +519,7 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 
 			var schemaRef map[string]interface{}
 			if shouldInlineOperationSchema(errorType) {
-				schemaRef = generateSchema(errorType)
+				schemaRef = generateSchema(errorType, o.validation)
 			} else {
 				schemaRef = map[string]interface{}{
 					"$ref": "#/components/schemas/" + getTypeName(errorType),
@@ -458,18 +541,23 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 		// uses that shape (schema + a representative example built via the same
 		// reflection helpers used at runtime). Otherwise we fall back to the
 		// built-in ErrorEnvelope.
+		// Under Config.ProblemDetails every default entry is documented as
+		// application/problem+json instead.
 		shape := o.config.DefaultErrorShape
 		defaultErrContent := func(cat errorCategory, envExample func() ErrorEnvelope) map[string]interface{} {
+			if o.config.ProblemDetails {
+				return o.problemSpecContent(op.Path, envExample())
+			}
 			if shape != nil {
-				return map[string]interface{}{
-					"schema":  errorSchemaRef(reflect.TypeOf(shape)),
+				return map[string]interface{}{"application/json": map[string]interface{}{
+					"schema":  errorSchemaRef(reflect.TypeOf(shape), o.validation),
 					"example": materializeError(shape, cat),
-				}
+				}}
 			}
-			return map[string]interface{}{
+			return map[string]interface{}{"application/json": map[string]interface{}{
 				"schema":  map[string]interface{}{"$ref": "#/components/schemas/ErrorEnvelope"},
 				"example": envExample(),
-			}
+			}}
 		}
 
 		// All three default envelope responses (422 validation, 400 parse,
@@ -479,14 +567,18 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			// 422 always uses ErrorEnvelope so per-field info (loc / constraint /
 			// field / value) stays first-class for clients building form-level UX,
 			// even when DefaultErrorShape is set for the other error categories.
+			validationContent := map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema":  map[string]interface{}{"$ref": "#/components/schemas/ErrorEnvelope"},
+					"example": exampleValidationEnvelope(o.specMessages()),
+				},
+			}
+			if o.config.ProblemDetails {
+				validationContent = o.problemSpecContent(op.Path, exampleValidationEnvelope(o.specMessages()))
+			}
 			responses["422"] = map[string]interface{}{
 				"description": "Validation error",
-				"content": map[string]interface{}{
-					"application/json": map[string]interface{}{
-						"schema":  map[string]interface{}{"$ref": "#/components/schemas/ErrorEnvelope"},
-						"example": exampleValidationEnvelope(),
-					},
-				},
+				"content":     validationContent,
 			}
 			// Only POST/PUT/PATCH can produce JSON parse / type-mismatch errors.
 			// The 400 covers both syntactically-malformed bodies and well-formed
@@ -496,12 +588,70 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			if op.Method == "POST" || op.Method == "PUT" || op.Method == "PATCH" {
 				responses["400"] = map[string]interface{}{
 					"description": "Invalid request body (malformed JSON or wrong field type)",
-					"content": map[string]interface{}{"application/json": defaultErrContent(errorCategory{
+					"content": defaultErrContent(errorCategory{
 						Code:    400,
 						Type:    errTypeTypeMismatch,
 						Message: fmt.Sprintf(typeMismatchMsgFmt, "age", "int", "string"),
 						Details: "int",
-					}, exampleParseEnvelope)},
+					}, exampleParseEnvelope),
+				}
+			}
+			// Per-operation body limits surface as 413 / 415 in the same shape
+			// as the other parse-phase errors.
+			if op.Method == "POST" || op.Method == "PUT" || op.Method == "PATCH" {
+				if limit := op.Options.MaxBodySize; limit > 0 {
+					responses["413"] = map[string]interface{}{
+						"description": "Request body too large",
+						"content": defaultErrContent(
+							newPayloadTooLargeError(limit+1, limit).category(), examplePayloadTooLargeEnvelope(limit)),
+					}
+				}
+				if accepted := op.Options.Consumes; len(accepted) > 0 {
+					responses["415"] = map[string]interface{}{
+						"description": "Unsupported request media type",
+						"content": defaultErrContent(
+							newUnsupportedMediaTypeError("text/plain", accepted).category(), exampleUnsupportedMediaTypeEnvelope(accepted)),
+					}
+				}
+			}
+			// Versions selected by header or media type can be unsupported.
+			if v := o.config.Versioning; v != nil && op.Version != "" && v.Strategy != VersionByPath {
+				entry := o.unsupportedVersionEntry("0")
+				responses["406"] = map[string]interface{}{
+					"description": "Unsupported API version",
+					"content": defaultErrContent(errorCategory{
+						Code:    entry.Code,
+						Type:    entry.Type,
+						Message: entry.Msg,
+						Details: entry.Constraint,
+					}, func() ErrorEnvelope { return exampleEnvelope(entry) }),
+				}
+			}
+			// Recovered panics surface as a 500 in the default error shape.
+			if o.config.RecoverPanics {
+				responses["500"] = map[string]interface{}{
+					"description": "Internal server error",
+					"content": defaultErrContent(
+						internalErrorCategory(), exampleInternalErrorEnvelope),
+				}
+			}
+			// Operations past their sunset answer 410 when it is enforced.
+			if o.config.EnforceSunset && !op.Options.Sunset.IsZero() {
+				cat := errorCategory{Code: fiber.StatusGone, Type: errTypeGone, Message: goneMessage(op.Options.Sunset)}
+				if op.Options.Successor != "" {
+					cat.Details = "successor=" + op.Options.Successor
+				}
+				responses["410"] = map[string]interface{}{
+					"description": "Operation retired",
+					"content":     defaultErrContent(cat, func() ErrorEnvelope { return exampleEnvelope(goneEntry(cat)) }),
+				}
+			}
+			// Rate-limited operations answer 429 once a client exhausts its limit.
+			if limit := op.Options.RateLimit; limit != nil {
+				responses["429"] = map[string]interface{}{
+					"description": "Rate limit exceeded",
+					"headers":     rateLimitSpecHeaders(),
+					"content":     defaultErrContent(rateLimitCategory(limit, 1), exampleRateLimitEnvelope(limit)),
 				}
 			}
 			// When UseNotFoundHandler() has been installed, every operation can
@@ -509,11 +659,11 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			if o.notFoundInstalled {
 				responses["404"] = map[string]interface{}{
 					"description": "Route not found",
-					"content": map[string]interface{}{"application/json": defaultErrContent(errorCategory{
+					"content": defaultErrContent(errorCategory{
 						Code:    404,
 						Type:    errTypeNotFound,
 						Message: "no route matches GET /users/42",
-					}, exampleNotFoundEnvelope)},
+					}, exampleNotFoundEnvelope),
 				}
 			}
 		}
@@ -525,9 +675,16 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 			if errInst == nil {
 				continue
 			}
-			code, response := buildErrorResponse(errInst)
+			code, response := buildErrorResponse(errInst, o.validation)
 			responses[statusCodeKey(code)] = response
 		}
+		// Idempotent operations answer about the Idempotency-Key: 400
+		// missing / invalid, 409 in flight, 422 reused, 503 store down. They
+		// are merged last so declared errors sharing a status keep their shape
+		// next to the envelope.
+		if idem := op.Options.Idempotency; idem != nil && !suppressAllDefaultErrors {
+			addIdempotencyResponses(responses, idem, defaultErrContent)
+		}
 
 		enhancedOptions["responses"] = responses
 		pathItem[strings.ToLower(op.Method)] = enhancedOptions
@@ -536,6 +693,55 @@ func (o *OApiApp) GenerateOpenAPISpec() map[string]interface{} {
 	return spec
 }
 
+// collectSpecTypes returns every type exposed under components.schemas, keyed
+// by schema name. It is the single source of truth for the spec and for the
+// code generators, so they always agree on which types exist.
+func (o *OApiApp) collectSpecTypes() map[string]reflect.Type {
+	allTypes := make(map[string]reflect.Type)
+
+	for _, op := range o.operations {
+		// Hidden operations contribute neither to the spec paths nor to the
+		// schemas index, so a type only ever referenced by a hidden route does
+		// not leak as a public component.
+		if op.Options.Hidden {
+			continue
+		}
+		if op.InputType != nil {
+			collectAllTypes(op.InputType, allTypes)
+		}
+		if op.OutputType != nil {
+			collectAllTypes(op.OutputType, allTypes)
+		}
+		if op.ErrorType != nil && !isEmptyStruct(op.ErrorType) {
+			collectAllTypes(op.ErrorType, allTypes)
+		}
+		// Each declared custom error contributes its own type to components.schemas
+		// so multiple error responses sharing a struct ($ref via the same name)
+		// stay deduplicated.
+		for _, errInst := range op.Options.Errors {
+			if errInst == nil {
+				continue
+			}
+			collectAllTypes(reflect.TypeOf(errInst), allTypes)
+		}
+	}
+
+	// When the user opted into a unified shape via Config.DefaultErrorShape, the
+	// per-operation responses reference it by $ref. Make sure its schema (and
+	// any nested types) is collected so we never emit dangling references.
+	if o.config.DefaultErrorShape != nil {
+		collectAllTypes(reflect.TypeOf(o.config.DefaultErrorShape), allTypes)
+	}
+
+	// Always expose the default error envelope shape so every route can $ref it.
+	collectAllTypes(reflect.TypeOf(ErrorEnvelope{}), allTypes)
+	if o.config.ProblemDetails {
+		collectAllTypes(reflect.TypeOf(ProblemDetails{}), allTypes)
+	}
+
+	return allTypes
+}
+
 // GenerateOpenAPISpecYAML generates the OpenAPI spec in YAML format
 func (o *OApiApp) GenerateOpenAPISpecYAML() (string, error) {
 	spec := o.GenerateOpenAPISpec()
@@ -788,8 +994,9 @@ func shouldInlineOperationSchema(t reflect.Type) bool {
 	return false
 }
 
-// generateSchema generates an OpenAPI schema from a Go type
-func generateSchema(t reflect.Type) map[string]interface{} {
+// generateSchema generates an OpenAPI schema from a Go type. rules documents
+// the app's custom validate tags; it may be nil.
+func generateSchema(t reflect.Type, rules *validationRules) map[string]interface{} {
 	if t == nil {
 		return map[string]interface{}{
 			"type": "object",
@@ -849,8 +1056,8 @@ func generateSchema(t reflect.Type) map[string]interface{} {
 			fieldSchema := generateFieldSchema(field.Type)
 
 			// Add validation info from tags
-			if validateTag := field.Tag.Get("validate"); validateTag != "" {
-				addValidationToSchema(fieldSchema, validateTag)
+			if validateTag := rules.expand(field.Tag.Get("validate")); validateTag != "" {
+				addValidationToSchema(fieldSchema, validateTag, rules)
 
 				// Check if field is required
 				if strings.Contains(validateTag, "required") {
@@ -858,6 +1065,10 @@ func generateSchema(t reflect.Type) map[string]interface{} {
 				}
 			}
 
+			if isDeprecatedField(field.Tag.Get("deprecated")) {
+				fieldSchema["deprecated"] = true
+			}
+
 			properties[fieldName] = fieldSchema
 		}
 
@@ -1005,14 +1216,16 @@ func generateFieldSchema(t reflect.Type) map[string]interface{} {
 	return schema
 }
 
-// addValidationToSchema adds validation constraints to schema based on validate tags
-func addValidationToSchema(schema map[string]interface{}, validateTag string) {
-	rules := strings.Split(validateTag, ",")
-
-	for _, rule := range rules {
+// addValidationToSchema adds validation constraints to schema based on validate
+// tags. Custom tags registered with a Schema mapping in rules (nil allowed)
+// contribute their keywords.
+func addValidationToSchema(schema map[string]interface{}, validateTag string, rules *validationRules) {
+	for _, rule := range strings.Split(validateTag, ",") {
 		rule = strings.TrimSpace(rule)
 
-		if rule == "required" {
+		if rules.addCustomSchema(schema, rule) {
+			continue
+		} else if rule == "required" {
 			// Required is handled at the object level
 			continue
 		} else if strings.HasPrefix(rule, "min=") {
@@ -1075,32 +1288,125 @@ func Method[TInput any, TOutput any, TError any](
 ) {
 	app := router.GetApp()
 	fullPath := router.GetPrefix() + path
+	if group, ok := router.(*OApiGroup); ok {
+		options = group.defaults.apply(options)
+	}
 
 	// Validate path parameters with the input struct
 	if err := validatePathParams[TInput](fullPath); err != nil {
 		panic(fmt.Sprintf("Path validation failed for %s: %v", fullPath, err))
 	}
+	if limit := options.RateLimit; limit != nil && (limit.Requests <= 0 || limit.Window <= 0) {
+		panic(fmt.Sprintf("Invalid rate limit for %s: %d requests per %s", fullPath, limit.Requests, limit.Window))
+	}
+	if options.Idempotency != nil && m != http.MethodPost && m != http.MethodPatch {
+		panic(fmt.Sprintf("Idempotency is only supported on POST and PATCH, not on %s %s", m, fullPath))
+	}
 
+	// One operation per API version it belongs to (a single unversioned one
+	// when Config.Versioning is not set).
+	for _, route := range app.versionRoutes(fullPath, options.Versions) {
+		registerOperation(app, m, route.path, route.version, handler, options)
+	}
+}
+
+// registerOperation records an operation for the OpenAPI documentation and
+// installs its fiber handler chain.
+func registerOperation[TInput any, TOutput any, TError any](
+	app *OApiApp,
+	m string,
+	fullPath string,
+	version string,
+	handler HandlerFunc[TInput, TOutput, TError],
+	options OpenAPIOptions,
+) {
 	// Register the operation for OpenAPI documentation with type information
 	var inputZero TInput
 	var outputZero TOutput
 	var errorZero TError
 
-	app.operations = append(app.operations, OpenAPIOperation{
+	operation := OpenAPIOperation{
 		Method:     m,
 		Path:       fullPath,
 		Options:    options,
 		InputType:  reflect.TypeOf(inputZero),
 		OutputType: reflect.TypeOf(outputZero),
 		ErrorType:  reflect.TypeOf(errorZero),
-	})
+		Version:    version,
+	}
+	app.operations = append(app.operations, operation)
+	checker := &responseChecker{op: operation, rules: app.validation}
 
 	inputType := reflect.TypeOf(inputZero)
 
+	// respond answers a request whose input was parsed: runs the handler and
+	// sends its output or error (or the mock response).
+	respond := func(c fiber.Ctx, input TInput) error {
+		if app.config.Mock {
+			return app.serveMock(c, checker)
+		}
+
+		run := hookRunFrom(c)
+		run.begin()
+		output, customErr := handler(c, input)
+		run.handled(c, customErr)
+
+		if !isZero(customErr) {
+			return app.handleCustomError(c, customErr)
+		}
+		defer run.serialized()
+
+		if app.config.ResponseValidation != ResponseValidationOff {
+			if handled, err := app.validateResponse(c, checker, output); handled {
+				return err
+			}
+		}
+
+		if err := c.JSON(output); err != nil {
+			if fallbackErr := c.Status(500).JSON(ErrorResponse{
+				Code:    500,
+				Details: "Failed to serialize response",
+				Type:    "serialization_error",
+			}); fallbackErr != nil {
+				// Both serializations failed, return original error to Fiber
+				return err
+			}
+			return nil
+		}
+		return nil
+	}
+
 	// Wrapper
+	deprecated := options.isDeprecated()
 	fiberHandler := func(c fiber.Ctx) error {
+		if deprecated {
+			app.setDeprecationHeaders(c, &operation)
+			if app.sunsetPassed(options) {
+				return app.serveGone(c, options)
+			}
+		}
+
+		// Limits keyed by IP or API key are enforced before any parsing work,
+		// those keyed by user once authorization has identified the caller,
+		// or once the request is rejected (charged to the user when
+		// authentication got that far, to the IP otherwise).
+		limit := options.RateLimit
+		if limit != nil && !limit.rateLimitedAfterAuth() {
+			if handled, err := app.enforceRateLimit(c, &operation); handled {
+				return err
+			}
+		}
+
 		input, err := parseInput[TInput](app, c, fullPath, &options)
 		if err != nil {
+			// Rejected requests are charged too, so floods of malformed or
+			// unauthenticated requests are limited like any other.
+			if limit != nil && limit.rateLimitedAfterAuth() {
+				if handled, err := app.enforceRateLimit(c, &operation); handled {
+					return err
+				}
+			}
+			app.markErrorType(c, inputErrorType(err))
 			// Custom handlers, when configured, still take precedence and receive
 			// the raw error — they may produce any shape they want.
 			if authErr, ok := errors.AsType[*AuthError](err); ok && app.config.AuthErrorHandler != nil {
@@ -1109,43 +1415,47 @@ func Method[TInput any, TOutput any, TError any](
 			if app.config.ValidationErrorHandler != nil {
 				return app.config.ValidationErrorHandler(c, err)
 			}
+			// Problem Details mode renders every category, validation included,
+			// as one RFC 9457 document carrying the envelope's entries.
+			if app.config.ProblemDetails {
+				envelope, status := buildEnvelope(c, app.config, app.requestMessages(c), inputType, err)
+				return app.sendProblem(c, status, envelope.Errors)
+			}
 			// If the user opted into a unified shape, emit it for parse / auth /
 			// generic errors. Validation errors keep the rich ErrorEnvelope shape
 			// regardless — collapsing a multi-field validation failure into a
 			// single flat struct would lose the per-field info (loc / constraint
 			// / field) that clients rely on for form-level UX.
 			if app.config.DefaultErrorShape != nil && !isValidationError(err) {
-				cat := categorizeError(err)
+				cat := categorizeError(err, app.requestMessages(c))
 				return c.Status(cat.Code).JSON(materializeError(app.config.DefaultErrorShape, cat))
 			}
 			// Default response: structured envelope, one entry per failing field,
 			// status code chosen per error category (422 validation, 400 parse,
 			// 401/403 auth).
-			envelope, status := buildEnvelope(c, app.config, inputType, err)
+			envelope, status := buildEnvelope(c, app.config, app.requestMessages(c), inputType, err)
 			return c.Status(status).JSON(envelope)
 		}
 
-		output, customErr := handler(c, input)
-
-		if !isZero(customErr) {
-			return handleCustomError(c, customErr)
-		}
-
-		if err := c.JSON(output); err != nil {
-			if fallbackErr := c.Status(500).JSON(ErrorResponse{
-				Code:    500,
-				Details: "Failed to serialize response",
-				Type:    "serialization_error",
-			}); fallbackErr != nil {
-				// Both serializations failed, return original error to Fiber
+		if limit != nil && limit.rateLimitedAfterAuth() {
+			if handled, err := app.enforceRateLimit(c, &operation); handled {
 				return err
 			}
-			return nil
 		}
-		return nil
+
+		// With Idempotency, a retry gets the stored response instead.
+		if options.Idempotency != nil {
+			return app.serveIdempotent(c, &operation, func() error { return respond(c, input) })
+		}
+		return respond(c, input)
+	}
+
+	if app.config.RecoverPanics {
+		fiberHandler = app.recoverPanics(fiberHandler)
 	}
 
-	app.f.Add([]string{m}, fullPath, fiberHandler)
+	handlers := app.operationHandlers(&operation, fiberHandler)
+	app.f.Add([]string{m}, fullPath, handlers[0], handlers[1:]...)
 }
 
 // Get defines a GET operation for the OpenAPI documentation
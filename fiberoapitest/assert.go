@@ -0,0 +1,119 @@
+package fiberoapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	fiberoapi "github.com/labbs/fiber-oapi/v3"
+)
+
+// Entry matches an ErrorEnvelope entry. Zero-valued fields are not compared,
+// so Entry{Type: "validation_error"} matches any validation entry. Loc
+// elements are compared by their printed form, which makes the int 2 match
+// the float64 2 produced by JSON decoding.
+type Entry struct {
+	Type       string
+	Code       int
+	Loc        []any
+	Field      string
+	Constraint string
+}
+
+func (m Entry) matches(e fiberoapi.ValidationErrorEntry) bool {
+	if m.Type != "" && m.Type != e.Type {
+		return false
+	}
+	if m.Code != 0 && m.Code != e.Code {
+		return false
+	}
+	if m.Field != "" && m.Field != e.Field {
+		return false
+	}
+	if m.Constraint != "" && m.Constraint != e.Constraint {
+		return false
+	}
+	if m.Loc != nil {
+		if len(m.Loc) != len(e.Loc) {
+			return false
+		}
+		for i := range m.Loc {
+			if fmt.Sprint(m.Loc[i]) != fmt.Sprint(e.Loc[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (m Entry) String() string {
+	var parts []string
+	if m.Type != "" {
+		parts = append(parts, "type="+m.Type)
+	}
+	if m.Code != 0 {
+		parts = append(parts, fmt.Sprintf("code=%d", m.Code))
+	}
+	if m.Loc != nil {
+		parts = append(parts, fmt.Sprintf("loc=%v", m.Loc))
+	}
+	if m.Field != "" {
+		parts = append(parts, "field="+m.Field)
+	}
+	if m.Constraint != "" {
+		parts = append(parts, "constraint="+m.Constraint)
+	}
+	return "{" + strings.Join(parts, " ") + "}"
+}
+
+// FindEntry returns the first envelope entry matching m.
+func (r *Response[TOut]) FindEntry(m Entry) (fiberoapi.ValidationErrorEntry, bool) {
+	if r.Envelope == nil {
+		return fiberoapi.ValidationErrorEntry{}, false
+	}
+	for _, e := range r.Envelope.Errors {
+		if m.matches(e) {
+			return e, true
+		}
+	}
+	return fiberoapi.ValidationErrorEntry{}, false
+}
+
+// AssertStatus reports an error, including the response body, when the
+// response status is not want.
+func AssertStatus[TOut any](t testing.TB, r *Response[TOut], want int) bool {
+	t.Helper()
+	if r.Status != want {
+		t.Errorf("status = %d, want %d; body: %s", r.Status, want, r.Body)
+		return false
+	}
+	return true
+}
+
+// AssertEntry reports an error when the response carries no envelope entry
+// matching m, and returns the matching entry otherwise.
+func AssertEntry[TOut any](t testing.TB, r *Response[TOut], m Entry) fiberoapi.ValidationErrorEntry {
+	t.Helper()
+	e, ok := r.FindEntry(m)
+	if !ok {
+		t.Errorf("no envelope entry matches %s; got %s", m, describeEntries(r))
+	}
+	return e
+}
+
+// AssertNoEntry reports an error when an envelope entry matches m.
+func AssertNoEntry[TOut any](t testing.TB, r *Response[TOut], m Entry) {
+	t.Helper()
+	if e, ok := r.FindEntry(m); ok {
+		t.Errorf("unexpected envelope entry matching %s: %+v", m, e)
+	}
+}
+
+func describeEntries[TOut any](r *Response[TOut]) string {
+	if r.Envelope == nil {
+		return fmt.Sprintf("no envelope (status %d, body %s)", r.Status, r.Body)
+	}
+	data, _ := json.Marshal(r.Envelope.Errors)
+	return string(data)
+}
@@ -0,0 +1,34 @@
+package fiberoapitest
+
+import (
+	"testing"
+
+	fiberoapi "github.com/labbs/fiber-oapi/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Matches(t *testing.T) {
+	e := fiberoapi.ValidationErrorEntry{
+		Type:       "validation_error",
+		Code:       422,
+		Loc:        []any{"body", "items", float64(2), "id"},
+		Field:      "id",
+		Constraint: "required",
+	}
+
+	assert.True(t, Entry{}.matches(e), "zero matcher matches anything")
+	assert.True(t, Entry{Loc: []any{"body", "items", 2, "id"}}.matches(e), "ints match decoded floats")
+	assert.True(t, Entry{Type: "validation_error", Field: "id", Constraint: "required", Code: 422}.matches(e))
+	assert.False(t, Entry{Loc: []any{"body", "items"}}.matches(e), "loc must match in full")
+	assert.False(t, Entry{Constraint: "min=1"}.matches(e))
+	assert.False(t, Entry{Code: 400}.matches(e))
+
+	assert.Equal(t, "{type=validation_error loc=[body name]}", Entry{Type: "validation_error", Loc: []any{"body", "name"}}.String())
+}
+
+func TestFindEntry_NoEnvelope(t *testing.T) {
+	r := &Response[struct{}]{Status: 500, Body: []byte("boom")}
+	_, ok := r.FindEntry(Entry{})
+	assert.False(t, ok)
+	assert.Contains(t, describeEntries(r), "no envelope (status 500")
+}
@@ -0,0 +1,312 @@
+// Package fiberoapitest provides a typed in-memory client for testing
+// fiber-oapi applications.
+//
+// Call serialises a typed input the way parseInput reads it back — uri
+// fields into the path, query fields into the query string, header fields
+// into headers and the remaining fields into a JSON body — runs the request
+// through fiber's in-memory Test and decodes the response:
+//
+//	resp := fiberoapitest.MustCall[GetUserInput, User](t, oapi, "GET", "/users/:id", GetUserInput{ID: "42"})
+//	fiberoapitest.AssertStatus(t, resp, 200)
+//	assert.Equal(t, "42", resp.Output.ID)
+//
+//	resp = fiberoapitest.MustCall[CreateUserInput, User](t, oapi, "POST", "/users", CreateUserInput{})
+//	fiberoapitest.AssertEntry(t, resp, fiberoapitest.Entry{Type: "validation_error", Loc: []any{"body", "name"}, Constraint: "required"})
+package fiberoapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	fiberoapi "github.com/labbs/fiber-oapi/v3"
+)
+
+// Response is the decoded result of a Call.
+type Response[TOut any] struct {
+	Status int
+	Header http.Header
+	Body   []byte // raw response body
+
+	// Output is decoded from 2xx responses.
+	Output TOut
+	// Envelope is set for non-2xx responses whose body is an ErrorEnvelope
+	// with at least one entry.
+	Envelope *fiberoapi.ErrorEnvelope
+	// Error holds a pointer to the decoded error for non-2xx responses: the
+	// type declared in OpenAPIOptions.Errors for the status when there is
+	// one, otherwise the handler's concrete TError struct when the body is
+	// not an envelope. It is nil when neither applies.
+	Error any
+}
+
+// Option customises the request built by Call.
+type Option func(*request)
+
+type request struct {
+	header      http.Header
+	body        []byte
+	contentType string
+	rawBody     bool
+	timeout     time.Duration
+}
+
+// WithHeader adds a request header on top of those derived from the input
+// (e.g. Authorization or X-Request-Id).
+func WithHeader(key, value string) Option {
+	return func(r *request) { r.header.Add(key, value) }
+}
+
+// WithBody replaces the JSON body derived from the input, e.g. to exercise
+// malformed payloads or type mismatches.
+func WithBody(contentType string, body []byte) Option {
+	return func(r *request) {
+		r.body, r.contentType, r.rawBody = body, contentType, true
+	}
+}
+
+// WithTimeout overrides fiber's default 1s Test timeout. Zero disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(r *request) { r.timeout = d }
+}
+
+// Call builds a request for the route template path (e.g. "/users/:id") from
+// input, runs it through app's in-memory Test and decodes the response. The
+// returned error covers request construction and transport only; HTTP error
+// statuses are reported through the Response.
+func Call[TIn, TOut any](app *fiberoapi.OApiApp, method, path string, input TIn, opts ...Option) (*Response[TOut], error) {
+	method = strings.ToUpper(method)
+	req := &request{header: http.Header{}, timeout: time.Second}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	target, err := encodeInput(method, path, input, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq := httptest.NewRequest(method, target, body)
+	for k, vs := range req.header {
+		for _, v := range vs {
+			httpReq.Header.Add(k, v)
+		}
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+
+	httpResp, err := app.FiberApp().Test(httpReq, fiber.TestConfig{Timeout: req.timeout, FailOnTimeout: true})
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &Response[TOut]{Status: httpResp.StatusCode, Header: httpResp.Header, Body: raw}
+	if err := resp.decode(app, method, path); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
+
+// MustCall is Call that fails the test on construction or transport errors.
+func MustCall[TIn, TOut any](t testing.TB, app *fiberoapi.OApiApp, method, path string, input TIn, opts ...Option) *Response[TOut] {
+	t.Helper()
+	resp, err := Call[TIn, TOut](app, method, path, input, opts...)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	return resp
+}
+
+func (r *Response[TOut]) decode(app *fiberoapi.OApiApp, method, path string) error {
+	if r.Status >= 200 && r.Status < 300 {
+		if len(r.Body) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(r.Body, &r.Output); err != nil {
+			return fmt.Errorf("decode %d response into %T: %w", r.Status, r.Output, err)
+		}
+		return nil
+	}
+	if len(r.Body) == 0 {
+		return nil
+	}
+
+	var env fiberoapi.ErrorEnvelope
+	if json.Unmarshal(r.Body, &env) == nil && len(env.Errors) > 0 {
+		r.Envelope = &env
+	}
+
+	op, ok := findOperation(app, method, path)
+	if !ok {
+		return nil
+	}
+	errType := op.DeclaredErrorType(r.Status)
+	if errType == nil && r.Envelope == nil && op.ErrorType != nil {
+		if t := deref(op.ErrorType); t.Kind() == reflect.Struct && t.NumField() > 0 {
+			errType = op.ErrorType
+		}
+	}
+	if errType == nil {
+		return nil
+	}
+	ptr := reflect.New(deref(errType))
+	if err := json.Unmarshal(r.Body, ptr.Interface()); err != nil {
+		return fmt.Errorf("decode %d response into %s: %w", r.Status, errType, err)
+	}
+	r.Error = ptr.Interface()
+	return nil
+}
+
+func findOperation(app *fiberoapi.OApiApp, method, path string) (fiberoapi.OpenAPIOperation, bool) {
+	for _, op := range app.GetOperations() {
+		if op.Method == method && op.Path == path {
+			return op, true
+		}
+	}
+	return fiberoapi.OpenAPIOperation{}, false
+}
+
+// encodeInput fills the path template, query string, headers and body from
+// input and returns the request target.
+func encodeInput(method, path string, input any, req *request) (string, error) {
+	pathValues := map[string]string{}
+	query := url.Values{}
+	bodyKeys := map[string]bool{} // JSON keys of uri / query / header fields, dropped from the body
+
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if err := collectParams(v, pathValues, query, req.header, bodyKeys); err != nil {
+			return "", err
+		}
+	}
+
+	target, err := fiberoapi.FillPath(path, pathValues)
+	if err != nil {
+		return "", fmt.Errorf("%w (set the fields tagged uri)", err)
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	if !req.rawBody && (method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch) && v.Kind() == reflect.Struct {
+		body, err := encodeBody(input, bodyKeys)
+		if err != nil {
+			return "", err
+		}
+		if body != nil {
+			req.body, req.contentType = body, "application/json"
+		}
+	}
+	return target, nil
+}
+
+func collectParams(v reflect.Value, pathValues map[string]string, query url.Values, header http.Header, bodyKeys map[string]bool) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		uri, q, h := field.Tag.Get("uri"), field.Tag.Get("query"), field.Tag.Get("header")
+		if uri == "" && q == "" && h == "" {
+			if field.Anonymous && deref(field.Type).Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+				for fv.Kind() == reflect.Ptr {
+					if fv.IsNil() {
+						break
+					}
+					fv = fv.Elem()
+				}
+				if fv.Kind() == reflect.Struct {
+					if err := collectParams(fv, pathValues, query, header, bodyKeys); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		bodyKeys[jsonKey(field)] = true
+
+		// Zero query and header fields are left out; a zero uri field is a value.
+		if uri == "" && fv.IsZero() {
+			continue
+		}
+		values, err := fiberoapi.ParamValues(fv.Interface())
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		switch {
+		case uri != "":
+			if len(values) > 0 {
+				pathValues[uri] = values[0]
+			}
+		case q != "":
+			for _, s := range values {
+				query.Add(q, s)
+			}
+		case h != "":
+			for _, s := range values {
+				header.Add(h, s)
+			}
+		}
+	}
+	return nil
+}
+
+// encodeBody marshals input and drops the keys that belong to parameter
+// fields. It returns nil when no body field remains.
+func encodeBody(input any, paramKeys map[string]bool) ([]byte, error) {
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		// Not an object (custom marshaller) — send it as is.
+		return raw, nil
+	}
+	for k := range paramKeys {
+		delete(fields, k)
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
+
+func jsonKey(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
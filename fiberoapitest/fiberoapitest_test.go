@@ -0,0 +1,140 @@
+package fiberoapitest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	fiberoapi "github.com/labbs/fiber-oapi/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Paging struct {
+	Limit int `query:"limit" validate:"omitempty,max=100"`
+}
+
+type updateInput struct {
+	Paging
+	ID      string   `uri:"id" validate:"required"`
+	Tags    []string `query:"tag"`
+	Sort    string   `query:"sort" validate:"omitempty,oneof=asc desc"`
+	Tenant  string   `header:"X-Tenant"`
+	Name    string   `json:"name" validate:"required,min=2"`
+	Comment *string  `json:"comment,omitempty"`
+}
+
+type updateOutput struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Tags    []string `json:"tags"`
+	Tenant  string   `json:"tenant"`
+	Limit   int      `json:"limit"`
+	Comment string   `json:"comment"`
+}
+
+type notFoundError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type conflictError struct {
+	StatusCode int    `json:"statusCode"`
+	Reason     string `json:"reason"`
+}
+
+func newTestApp() *fiberoapi.OApiApp {
+	oapi := fiberoapi.New(fiber.New())
+	fiberoapi.Put(oapi, "/items/:id", func(c fiber.Ctx, in updateInput) (updateOutput, *conflictError) {
+		switch in.ID {
+		case "missing":
+			return updateOutput{}, &conflictError{StatusCode: 404, Reason: "gone"}
+		case "locked":
+			return updateOutput{}, &conflictError{StatusCode: 409, Reason: "locked"}
+		}
+		out := updateOutput{ID: in.ID, Name: in.Name, Tags: in.Tags, Tenant: in.Tenant, Limit: in.Limit}
+		if in.Comment != nil {
+			out.Comment = *in.Comment
+		}
+		return out, nil
+	}, fiberoapi.OpenAPIOptions{
+		OperationID: "updateItem",
+		Errors:      []any{&notFoundError{Code: 404}},
+	})
+	return oapi
+}
+
+func TestCall_SerialisesEverySource(t *testing.T) {
+	oapi := newTestApp()
+	comment := "hi"
+	resp := MustCall[updateInput, updateOutput](t, oapi, "PUT", "/items/:id", updateInput{
+		Paging:  Paging{Limit: 5},
+		ID:      "a-1",
+		Tags:    []string{"x", "y"},
+		Tenant:  "acme",
+		Name:    "Widget",
+		Comment: &comment,
+	})
+
+	AssertStatus(t, resp, 200)
+	assert.Equal(t, updateOutput{ID: "a-1", Name: "Widget", Tags: []string{"x", "y"}, Tenant: "acme", Limit: 5, Comment: "hi"}, resp.Output)
+	assert.Nil(t, resp.Envelope)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+}
+
+func TestCall_DecodesEnvelope(t *testing.T) {
+	oapi := newTestApp()
+	resp := MustCall[updateInput, updateOutput](t, oapi, "PUT", "/items/:id", updateInput{ID: "1", Name: "W", Sort: "random"},
+		WithHeader("X-Request-Id", "req-7"))
+
+	AssertStatus(t, resp, 422)
+	require.NotNil(t, resp.Envelope)
+	assert.Equal(t, "req-7", resp.Envelope.ResponseContext.ResponseID)
+	assert.Nil(t, resp.Error, "the envelope is not the handler's error type")
+	AssertEntry(t, resp, Entry{Type: "validation_error", Loc: []any{"body", "name"}, Constraint: "min=2"})
+	AssertEntry(t, resp, Entry{Loc: []any{"query", "sort"}, Constraint: "oneof=asc desc"})
+	AssertNoEntry(t, resp, Entry{Loc: []any{"path", "id"}})
+}
+
+func TestCall_DecodesDeclaredAndHandlerErrors(t *testing.T) {
+	oapi := newTestApp()
+
+	resp := MustCall[updateInput, updateOutput](t, oapi, "PUT", "/items/:id", updateInput{ID: "missing", Name: "Widget"})
+	AssertStatus(t, resp, 404)
+	declared, ok := resp.Error.(*notFoundError)
+	require.True(t, ok, "404 decodes into the type declared in Errors, got %T", resp.Error)
+	assert.Equal(t, 0, declared.Code, "the handler body has no code field")
+
+	resp = MustCall[updateInput, updateOutput](t, oapi, "PUT", "/items/:id", updateInput{ID: "locked", Name: "Widget"})
+	AssertStatus(t, resp, 409)
+	assert.Equal(t, &conflictError{StatusCode: 409, Reason: "locked"}, resp.Error)
+}
+
+func TestCall_RawBody(t *testing.T) {
+	oapi := newTestApp()
+	resp := MustCall[updateInput, updateOutput](t, oapi, "PUT", "/items/:id", updateInput{ID: "1"},
+		WithBody("application/json", []byte(`{"name": 42}`)))
+
+	AssertStatus(t, resp, 400)
+	AssertEntry(t, resp, Entry{Type: "type_error", Loc: []any{"body", "name"}})
+}
+
+func TestCall_MissingPathParam(t *testing.T) {
+	oapi := newTestApp()
+	_, err := Call[updateInput, updateOutput](oapi, "PUT", "/items/:id/revisions/:rev", updateInput{ID: "1", Name: "Widget"})
+	assert.ErrorContains(t, err, "missing path parameters for /items/:id/revisions/:rev: rev")
+}
+
+func TestEncodeInput_ZeroValues(t *testing.T) {
+	// A zero uri field fills its segment; zero query and header fields are
+	// left out.
+	req := &request{header: http.Header{}}
+	target, err := encodeInput("GET", "/items/:id", struct {
+		ID     int    `uri:"id"`
+		Page   int    `query:"page"`
+		Tenant string `header:"X-Tenant"`
+	}{}, req)
+	require.NoError(t, err)
+	assert.Equal(t, "/items/0", target)
+	assert.Empty(t, req.header)
+}
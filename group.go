@@ -9,6 +9,31 @@ type OApiGroup struct {
 	fiber.Router          // Embedded fiber.Router (includes all standard Fiber methods)
 	oapi         *OApiApp // Reference to the parent OApiApp
 	prefix       string   // Group prefix for path construction
+	defaults     GroupOptions
+}
+
+// GroupOptions are OpenAPI defaults applied to every operation registered on
+// a group and its sub-groups. Merge rules, with the operation as the most
+// specific level:
+//   - Tags and RequiredPermissions accumulate (group values first, no duplicates);
+//   - Errors accumulate, except that an operation declaring its own error for a
+//     status code replaces the group's, and Errors: []any{} on the operation
+//     still opts out of every error response;
+//   - Security, RequiredRoles (with RequireAllRoles) and Audience are replaced
+//     by the operation's value when it sets one;
+//   - Hidden is sticky: a hidden group hides all of its operations.
+//
+// A nested group merges its own GroupOptions onto its parent's with the same
+// rules.
+type GroupOptions struct {
+	Tags                []string
+	Security            any // Same values as OpenAPIOptions.Security
+	RequiredRoles       []string
+	RequireAllRoles     bool
+	RequiredPermissions []string
+	Errors              []any
+	Hidden              bool
+	Audience            string
 }
 
 // Implement OApiRouter interface for OApiGroup
@@ -36,6 +61,13 @@ func (app *OApiApp) Group(prefix string, handlers ...fiber.Handler) *OApiGroup {
 	}
 }
 
+// GroupWithOptions creates a new OApiGroup whose operations inherit options.
+func (app *OApiApp) GroupWithOptions(prefix string, options GroupOptions, handlers ...fiber.Handler) *OApiGroup {
+	g := app.Group(prefix, handlers...)
+	g.defaults = options
+	return g
+}
+
 // Group creates a new sub-group within this group
 func (g *OApiGroup) Group(prefix string, handlers ...fiber.Handler) *OApiGroup {
 	fullPrefix := g.prefix + prefix
@@ -43,12 +75,21 @@ func (g *OApiGroup) Group(prefix string, handlers ...fiber.Handler) *OApiGroup {
 	fiberGroup := g.oapi.f.Group(fullPrefix, handlersToAny(handlers)...)
 
 	return &OApiGroup{
-		Router: fiberGroup,
-		oapi:   g.oapi,
-		prefix: fullPrefix,
+		Router:   fiberGroup,
+		oapi:     g.oapi,
+		prefix:   fullPrefix,
+		defaults: g.defaults,
 	}
 }
 
+// GroupWithOptions creates a new sub-group whose defaults are options merged
+// onto this group's.
+func (g *OApiGroup) GroupWithOptions(prefix string, options GroupOptions, handlers ...fiber.Handler) *OApiGroup {
+	sub := g.Group(prefix, handlers...)
+	sub.defaults = g.defaults.merge(options)
+	return sub
+}
+
 func handlersToAny(handlers []fiber.Handler) []any {
 	if len(handlers) == 0 {
 		return nil
@@ -69,3 +110,77 @@ func Group(router OApiRouter, prefix string, handlers ...fiber.Handler) *OApiGro
 	}
 	panic("unsupported router type")
 }
+
+// GroupWithOptions creates a new group with OpenAPI defaults from an
+// OApiRouter (app or group)
+func GroupWithOptions(router OApiRouter, prefix string, options GroupOptions, handlers ...fiber.Handler) *OApiGroup {
+	if app, ok := router.(*OApiApp); ok {
+		return app.GroupWithOptions(prefix, options, handlers...)
+	} else if group, ok := router.(*OApiGroup); ok {
+		return group.GroupWithOptions(prefix, options, handlers...)
+	}
+	panic("unsupported router type")
+}
+
+// merge returns the defaults of a sub-group declaring child.
+func (d GroupOptions) merge(child GroupOptions) GroupOptions {
+	out := d
+	out.Tags = appendUnique(append([]string(nil), d.Tags...), child.Tags...)
+	out.RequiredPermissions = appendUnique(append([]string(nil), d.RequiredPermissions...), child.RequiredPermissions...)
+	out.Errors = mergeDeclaredErrors(d.Errors, child.Errors)
+	if child.Security != nil {
+		out.Security = child.Security
+	}
+	if len(child.RequiredRoles) > 0 {
+		out.RequiredRoles = child.RequiredRoles
+		out.RequireAllRoles = child.RequireAllRoles
+	}
+	if child.Audience != "" {
+		out.Audience = child.Audience
+	}
+	out.Hidden = d.Hidden || child.Hidden
+	return out
+}
+
+// apply merges the group defaults into the options of one operation.
+func (d GroupOptions) apply(options OpenAPIOptions) OpenAPIOptions {
+	options.Tags = appendUnique(append([]string(nil), d.Tags...), options.Tags...)
+	options.RequiredPermissions = appendUnique(append([]string(nil), d.RequiredPermissions...), options.RequiredPermissions...)
+	if options.Errors == nil || len(options.Errors) > 0 {
+		options.Errors = mergeDeclaredErrors(d.Errors, options.Errors)
+	}
+	if options.Security == nil {
+		options.Security = d.Security
+	}
+	if len(options.RequiredRoles) == 0 && len(d.RequiredRoles) > 0 {
+		options.RequiredRoles = d.RequiredRoles
+		options.RequireAllRoles = d.RequireAllRoles
+	}
+	if options.Audience == "" {
+		options.Audience = d.Audience
+	}
+	options.Hidden = options.Hidden || d.Hidden
+	return options
+}
+
+// mergeDeclaredErrors returns the errors of specific followed by the inherited
+// ones whose status code specific does not already declare.
+func mergeDeclaredErrors(inherited, specific []any) []any {
+	if len(inherited) == 0 {
+		return specific
+	}
+	declared := map[int]bool{}
+	out := make([]any, 0, len(inherited)+len(specific))
+	for _, e := range specific {
+		if e != nil {
+			declared[extractErrorStatusCode(e)] = true
+		}
+		out = append(out, e)
+	}
+	for _, e := range inherited {
+		if e != nil && !declared[extractErrorStatusCode(e)] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -1,10 +1,12 @@
 package fiberoapi
 
 import (
+	"net/http/httptest"
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGroupFunctionality(t *testing.T) {
@@ -126,3 +128,136 @@ func TestNestedGroups(t *testing.T) {
 	}
 	assert.True(t, found, "GET /api/v1/users/:id operation should be registered")
 }
+
+type groupNotFound struct {
+	Message string `json:"message"`
+}
+
+func (groupNotFound) HTTPStatus() int { return 404 }
+
+type groupConflict struct {
+	Message string `json:"message"`
+}
+
+func (groupConflict) HTTPStatus() int { return 409 }
+
+type groupMissingItem struct {
+	Item string `json:"item"`
+}
+
+func (groupMissingItem) HTTPStatus() int { return 404 }
+
+func groupOp(t *testing.T, oapi *OApiApp, method, path string) OpenAPIOperation {
+	t.Helper()
+	for _, op := range oapi.GetOperations() {
+		if op.Method == method && op.Path == path {
+			return op
+		}
+	}
+	t.Fatalf("operation %s %s not registered", method, path)
+	return OpenAPIOperation{}
+}
+
+func TestGroupOptions_Inherited(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app)
+	noop := func(c fiber.Ctx, in struct{}) (struct{}, error) { return struct{}{}, nil }
+
+	admin := GroupWithOptions(oapi, "/admin", GroupOptions{
+		Tags:                []string{"admin"},
+		Security:            []map[string][]string{{"bearerAuth": {}}},
+		RequiredRoles:       []string{"admin"},
+		RequiredPermissions: []string{"admin:read"},
+		Errors:              []any{groupNotFound{}, groupConflict{}},
+		Audience:            "internal",
+	})
+	Get(admin, "/stats", noop, OpenAPIOptions{})
+	Get(admin, "/users", noop, OpenAPIOptions{
+		Tags:                []string{"users", "admin"},
+		Security:            "disabled",
+		RequiredRoles:       []string{"support", "auditor"},
+		RequireAllRoles:     true,
+		RequiredPermissions: []string{"user:read"},
+		Errors:              []any{groupMissingItem{}},
+		Audience:            "partner",
+	})
+	Get(admin, "/ping", noop, OpenAPIOptions{Errors: []any{}})
+
+	stats := groupOp(t, oapi, "GET", "/admin/stats").Options
+	assert.Equal(t, []string{"admin"}, stats.Tags)
+	assert.Equal(t, []map[string][]string{{"bearerAuth": {}}}, stats.Security)
+	assert.Equal(t, []string{"admin"}, stats.RequiredRoles)
+	assert.Equal(t, []string{"admin:read"}, stats.RequiredPermissions)
+	assert.Equal(t, []any{groupNotFound{}, groupConflict{}}, stats.Errors)
+	assert.Equal(t, "internal", stats.Audience)
+
+	users := groupOp(t, oapi, "GET", "/admin/users").Options
+	assert.Equal(t, []string{"admin", "users"}, users.Tags, "tags accumulate without duplicates")
+	assert.Equal(t, "disabled", users.Security, "operation security wins")
+	assert.Equal(t, []string{"support", "auditor"}, users.RequiredRoles, "operation roles replace the group's")
+	assert.True(t, users.RequireAllRoles)
+	assert.Equal(t, []string{"admin:read", "user:read"}, users.RequiredPermissions)
+	assert.Equal(t, []any{groupMissingItem{}, groupConflict{}}, users.Errors, "operation 404 replaces the group's")
+	assert.Equal(t, "partner", users.Audience)
+
+	ping := groupOp(t, oapi, "GET", "/admin/ping").Options
+	assert.Equal(t, []any{}, ping.Errors, "explicit opt-out is kept")
+
+	spec := oapi.GenerateOpenAPISpec()
+	paths := spec["paths"].(map[string]interface{})
+	statsSpec := paths["/admin/stats"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, "internal", statsSpec["x-audience"])
+	assert.Contains(t, statsSpec["responses"], "409")
+}
+
+func TestGroupOptions_NestedAndHidden(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app)
+	noop := func(c fiber.Ctx, in struct{}) (struct{}, error) { return struct{}{}, nil }
+
+	api := GroupWithOptions(oapi, "/api", GroupOptions{Tags: []string{"api"}, Errors: []any{groupNotFound{}}})
+	plain := api.Group("/plain")
+	internal := GroupWithOptions(api, "/internal", GroupOptions{Tags: []string{"internal"}, Hidden: true, RequiredRoles: []string{"ops"}})
+	deeper := internal.GroupWithOptions("/deeper", GroupOptions{Errors: []any{groupMissingItem{}}})
+
+	Get(plain, "/a", noop, OpenAPIOptions{})
+	Get(deeper, "/b", noop, OpenAPIOptions{Tags: []string{"b"}})
+
+	a := groupOp(t, oapi, "GET", "/api/plain/a").Options
+	assert.Equal(t, []string{"api"}, a.Tags, "plain sub-groups inherit defaults")
+	assert.False(t, a.Hidden)
+
+	b := groupOp(t, oapi, "GET", "/api/internal/deeper/b").Options
+	assert.Equal(t, []string{"api", "internal", "b"}, b.Tags)
+	assert.True(t, b.Hidden, "hidden is sticky")
+	assert.Equal(t, []string{"ops"}, b.RequiredRoles)
+	assert.Equal(t, []any{groupMissingItem{}}, b.Errors)
+
+	paths := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/api/plain/a")
+	assert.NotContains(t, paths, "/api/internal/deeper/b")
+}
+
+func TestGroupOptions_RolesEnforced(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app, Config{
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+	})
+	admin := GroupWithOptions(oapi, "/admin", GroupOptions{RequiredRoles: []string{"admin"}})
+	Get(admin, "/stats", func(c fiber.Ctx, in struct{}) (fiber.Map, *ErrorResponse) {
+		return fiber.Map{"ok": true}, nil
+	}, OpenAPIOptions{})
+
+	for token, status := range map[string]int{"admin-token": 200, "valid-token": 403} {
+		req := httptest.NewRequest("GET", "/admin/stats", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, status, resp.StatusCode, token)
+	}
+}
@@ -0,0 +1,73 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestApp returns an OApiApp on a fresh fiber.App.
+func newTestApp(cfg ...Config) *OApiApp {
+	return New(fiber.New(), cfg...)
+}
+
+// testResponse is a response sent by sendRequest, with its body read.
+type testResponse struct {
+	*http.Response
+	body []byte
+}
+
+// sendRequest runs one request through oapi. A non-empty body is sent as
+// JSON. headers are name / value pairs; an empty value removes the header,
+// e.g. "Content-Type", "" to send a body without one.
+func sendRequest(t *testing.T, oapi *OApiApp, method, target, body string, headers ...string) testResponse {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		if headers[i+1] == "" {
+			req.Header.Del(headers[i])
+		} else {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+	}
+	resp, err := oapi.FiberApp().Test(req, fiber.TestConfig{Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return testResponse{Response: resp, body: raw}
+}
+
+// decode unmarshals the body into v.
+func (r testResponse) decode(t *testing.T, v any) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.body, v), "%s", r.body)
+}
+
+// envelope decodes the body as an ErrorEnvelope.
+func (r testResponse) envelope(t *testing.T) ErrorEnvelope {
+	t.Helper()
+	var env ErrorEnvelope
+	r.decode(t, &env)
+	return env
+}
+
+// problem decodes the body as a ProblemDetails served as
+// application/problem+json.
+func (r testResponse) problem(t *testing.T) ProblemDetails {
+	t.Helper()
+	assert.Equal(t, problemContentType, r.Header.Get(fiber.HeaderContentType), "%s", r.body)
+	var p ProblemDetails
+	r.decode(t, &p)
+	return p
+}
@@ -0,0 +1,201 @@
+package fiberoapi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// hooksLocalsKey is the fiber.Ctx locals key holding the *hookRun of the
+// current request when Config.Hooks is set.
+const hooksLocalsKey = "fiberoapi.hooks"
+
+// Hooks observes the request pipeline of every operation: parsing,
+// validation, authorization, the handler and the response. Each method
+// receives the operation serving the request, which is shared across requests
+// and must not be modified. Methods run synchronously on the request path and
+// must not write the response.
+//
+// Embed NopHooks to implement only some of the methods:
+//
+//	type slowLog struct{ fiberoapi.NopHooks }
+//
+//	func (slowLog) OnResponse(c fiber.Ctx, op *fiberoapi.OpenAPIOperation, r fiberoapi.ResponseInfo) {
+//		if r.Duration > time.Second {
+//			slog.Warn("slow request", "operation", op.Options.OperationID, "handler", r.Phases.Handler)
+//		}
+//	}
+type Hooks interface {
+	// OnRequestStart fires once the operation is selected, before its
+	// OpenAPIOptions.Middleware.
+	OnRequestStart(c fiber.Ctx, op *OpenAPIOperation)
+	// OnParsed fires after the path, query, body and header binding. err is
+	// the parse error (400, 413, 415) answered to the client, or nil.
+	OnParsed(c fiber.Ctx, op *OpenAPIOperation, err error, d time.Duration)
+	// OnValidationFailed fires when validate tags or the input's Validate
+	// method reject the request.
+	OnValidationFailed(c fiber.Ctx, op *OpenAPIOperation, err error, d time.Duration)
+	// OnAuthDecision fires after authorization ran for the operation.
+	OnAuthDecision(c fiber.Ctx, op *OpenAPIOperation, decision AuthDecision)
+	// OnHandlerError fires when the handler returns a non-zero error value.
+	OnHandlerError(c fiber.Ctx, op *OpenAPIOperation, err any, d time.Duration)
+	// OnResponse fires once the operation chain has returned.
+	OnResponse(c fiber.Ctx, op *OpenAPIOperation, r ResponseInfo)
+}
+
+// NopHooks implements Hooks with methods that do nothing.
+type NopHooks struct{}
+
+func (NopHooks) OnRequestStart(fiber.Ctx, *OpenAPIOperation)                           {}
+func (NopHooks) OnParsed(fiber.Ctx, *OpenAPIOperation, error, time.Duration)           {}
+func (NopHooks) OnValidationFailed(fiber.Ctx, *OpenAPIOperation, error, time.Duration) {}
+func (NopHooks) OnAuthDecision(fiber.Ctx, *OpenAPIOperation, AuthDecision)             {}
+func (NopHooks) OnHandlerError(fiber.Ctx, *OpenAPIOperation, any, time.Duration)       {}
+func (NopHooks) OnResponse(fiber.Ctx, *OpenAPIOperation, ResponseInfo)                 {}
+
+// AuthDecision is the outcome of the authorization of a request.
+type AuthDecision struct {
+	Allowed  bool
+	Status   int          // 401, 403 or 5xx when denied
+	Err      error        // Denial reason, usually an *AuthError
+	Auth     *AuthContext // Authenticated caller, when known
+	Duration time.Duration
+}
+
+// PhaseTimings is the time spent in each stage of the pipeline. Stages that
+// did not run are zero.
+type PhaseTimings struct {
+	Parse         time.Duration // Path, query, body and header binding
+	Validation    time.Duration // validate tags and the input's Validate method
+	Authorization time.Duration
+	Handler       time.Duration
+	Serialization time.Duration // Response validation and JSON encoding of the output
+}
+
+// ResponseInfo describes the response of a request.
+type ResponseInfo struct {
+	Status   int           // Status sent, or the one Fiber's error handler will send for Err
+	Duration time.Duration // From OnRequestStart to the end of the operation chain
+	Phases   PhaseTimings
+	Err      error // Error returned to Fiber by the chain, if any
+}
+
+// hookRun tracks one request for Config.Hooks. Its methods are no-ops on a
+// nil receiver, so the pipeline calls them unconditionally.
+type hookRun struct {
+	hooks  Hooks
+	op     *OpenAPIOperation
+	lapped time.Time // start of the current phase
+	phases PhaseTimings
+}
+
+// hookRunFrom returns the run of the current request, or nil when
+// Config.Hooks is not set.
+func hookRunFrom(c fiber.Ctx) *hookRun {
+	run, _ := c.Locals(hooksLocalsKey).(*hookRun)
+	return run
+}
+
+// observe runs next, the rest of the operation chain, between OnRequestStart
+// and OnResponse, and records the request in the metrics.
+func (o *OApiApp) observe(c fiber.Ctx, op *OpenAPIOperation, next func() error) error {
+	start := time.Now()
+	var run *hookRun
+	if o.config.Hooks != nil {
+		run = &hookRun{hooks: o.config.Hooks, op: op, lapped: start}
+		c.Locals(hooksLocalsKey, run)
+		run.hooks.OnRequestStart(c, op)
+	}
+
+	err := next()
+	elapsed := time.Since(start)
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		if fe, ok := errors.AsType[*fiber.Error](err); ok {
+			status = fe.Code
+		}
+	}
+	if run != nil {
+		run.hooks.OnResponse(c, op, ResponseInfo{
+			Status:   status,
+			Duration: elapsed,
+			Phases:   run.phases,
+			Err:      err,
+		})
+	}
+	if o.metrics != nil {
+		o.metrics.observe(op, status, errorTypeOf(c, status), elapsed)
+	}
+	return err
+}
+
+// begin starts a phase.
+func (r *hookRun) begin() {
+	if r != nil {
+		r.lapped = time.Now()
+	}
+}
+
+// lap ends the current phase and returns its duration.
+func (r *hookRun) lap() time.Duration {
+	if r == nil {
+		return 0
+	}
+	now := time.Now()
+	d := now.Sub(r.lapped)
+	r.lapped = now
+	return d
+}
+
+func (r *hookRun) parsed(c fiber.Ctx, err error) {
+	if r == nil {
+		return
+	}
+	r.phases.Parse = r.lap()
+	r.hooks.OnParsed(c, r.op, err, r.phases.Parse)
+}
+
+func (r *hookRun) validated(c fiber.Ctx, err error) {
+	if r == nil {
+		return
+	}
+	r.phases.Validation = r.lap()
+	if err != nil {
+		r.hooks.OnValidationFailed(c, r.op, err, r.phases.Validation)
+	}
+}
+
+func (r *hookRun) authorized(c fiber.Ctx, err error) {
+	if r == nil {
+		return
+	}
+	r.phases.Authorization = r.lap()
+	decision := AuthDecision{Allowed: err == nil, Err: err, Duration: r.phases.Authorization}
+	decision.Auth, _ = c.Locals("auth").(*AuthContext)
+	if err != nil {
+		decision.Status = fiber.StatusUnauthorized
+		if authErr, ok := errors.AsType[*AuthError](err); ok {
+			decision.Status = authErr.StatusCode
+		}
+	}
+	r.hooks.OnAuthDecision(c, r.op, decision)
+}
+
+func (r *hookRun) handled(c fiber.Ctx, customErr any) {
+	if r == nil {
+		return
+	}
+	r.phases.Handler = r.lap()
+	if !isZero(customErr) {
+		r.hooks.OnHandlerError(c, r.op, customErr, r.phases.Handler)
+	}
+}
+
+func (r *hookRun) serialized() {
+	if r != nil {
+		r.phases.Serialization = r.lap()
+	}
+}
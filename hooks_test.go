@@ -0,0 +1,192 @@
+package fiberoapi
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHooks records every hook call as "event:detail".
+type recordingHooks struct {
+	mu        sync.Mutex
+	events    []string
+	decisions []AuthDecision
+	responses []ResponseInfo
+}
+
+func (h *recordingHooks) add(e string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, e)
+}
+
+func (h *recordingHooks) OnRequestStart(c fiber.Ctx, op *OpenAPIOperation) {
+	h.add("start:" + op.Options.OperationID)
+}
+
+func (h *recordingHooks) OnParsed(c fiber.Ctx, op *OpenAPIOperation, err error, d time.Duration) {
+	h.add("parsed:" + errString(err))
+}
+
+func (h *recordingHooks) OnValidationFailed(c fiber.Ctx, op *OpenAPIOperation, err error, d time.Duration) {
+	h.add("validation_failed")
+}
+
+func (h *recordingHooks) OnAuthDecision(c fiber.Ctx, op *OpenAPIOperation, decision AuthDecision) {
+	h.mu.Lock()
+	h.decisions = append(h.decisions, decision)
+	h.mu.Unlock()
+	h.add("auth")
+}
+
+func (h *recordingHooks) OnHandlerError(c fiber.Ctx, op *OpenAPIOperation, err any, d time.Duration) {
+	h.add("handler_error")
+}
+
+func (h *recordingHooks) OnResponse(c fiber.Ctx, op *OpenAPIOperation, r ResponseInfo) {
+	h.mu.Lock()
+	h.responses = append(h.responses, r)
+	h.mu.Unlock()
+	h.add("response")
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return "error"
+}
+
+// hookedCreateUser sleeps, fails or panics depending on the name.
+func hookedCreateUser(c fiber.Ctx, in uniInput) (uniOutput, error) {
+	switch in.Name {
+	case "slow":
+		time.Sleep(5 * time.Millisecond)
+	case "fail":
+		return uniOutput{}, errors.New("boom")
+	case "panic":
+		panic("boom")
+	}
+	return uniOutput{Message: "ok"}, nil
+}
+
+func TestHooks_SuccessfulRequest(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{Hooks: hooks})
+	Post(oapi, "/users/:name", hookedCreateUser, OpenAPIOptions{OperationID: "createUser"})
+	require.Equal(t, 200, sendRequest(t, oapi, "POST", "/users/slow", `{}`).StatusCode)
+
+	assert.Equal(t, []string{"start:createUser", "parsed:ok", "response"}, hooks.events)
+	r := hooks.responses[0]
+	assert.Equal(t, 200, r.Status)
+	assert.NoError(t, r.Err)
+	assert.GreaterOrEqual(t, r.Phases.Handler, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, r.Duration, r.Phases.Handler+r.Phases.Parse+r.Phases.Serialization)
+}
+
+func TestHooks_ParseError(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{Hooks: hooks})
+	Post(oapi, "/users/:name", hookedCreateUser, OpenAPIOptions{OperationID: "createUser"})
+	require.Equal(t, 400, sendRequest(t, oapi, "POST", "/users/bob", `{"age":"x"}`).StatusCode)
+
+	assert.Equal(t, []string{"start:createUser", "parsed:error", "response"}, hooks.events)
+	assert.Equal(t, 400, hooks.responses[0].Status)
+	assert.Zero(t, hooks.responses[0].Phases.Handler)
+}
+
+func TestHooks_ValidationFailed(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{Hooks: hooks})
+	Post(oapi, "/users/:name", hookedCreateUser, OpenAPIOptions{OperationID: "createUser"})
+	require.Equal(t, 422, sendRequest(t, oapi, "POST", "/users/a", `{}`).StatusCode)
+
+	assert.Equal(t, []string{"start:createUser", "parsed:ok", "validation_failed", "response"}, hooks.events)
+	assert.Equal(t, 422, hooks.responses[0].Status)
+}
+
+func TestHooks_HandlerErrorAndPanic(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{Hooks: hooks, RecoverPanics: true, PanicHandler: func(fiber.Ctx, any, []byte) {}})
+	Post(oapi, "/users/:name", hookedCreateUser, OpenAPIOptions{OperationID: "createUser"})
+
+	require.Equal(t, 500, sendRequest(t, oapi, "POST", "/users/fail", `{}`).StatusCode)
+	assert.Equal(t, []string{"start:createUser", "parsed:ok", "handler_error", "response"}, hooks.events)
+	assert.Equal(t, 500, hooks.responses[0].Status)
+
+	hooks.events = nil
+	require.Equal(t, 500, sendRequest(t, oapi, "POST", "/users/panic", `{}`).StatusCode)
+	assert.Equal(t, []string{"start:createUser", "parsed:ok", "response"}, hooks.events)
+	assert.Equal(t, 500, hooks.responses[1].Status)
+}
+
+func TestHooks_AuthDecision(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{
+		EnableValidation:    true,
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+		Hooks:           hooks,
+	})
+	Get(oapi, "/admin", func(c fiber.Ctx, input struct{}) (fiber.Map, *ErrorResponse) {
+		return fiber.Map{"ok": true}, nil
+	}, WithRoles(OpenAPIOptions{OperationID: "admin"}, "admin"))
+
+	require.Equal(t, 200, sendRequest(t, oapi, "GET", "/admin", "", "Authorization", "Bearer admin-token").StatusCode)
+	require.Equal(t, 403, sendRequest(t, oapi, "GET", "/admin", "", "Authorization", "Bearer valid-token").StatusCode)
+	require.Equal(t, 401, sendRequest(t, oapi, "GET", "/admin", "").StatusCode)
+
+	require.Len(t, hooks.decisions, 3)
+	assert.True(t, hooks.decisions[0].Allowed)
+	assert.Equal(t, "admin-456", hooks.decisions[0].Auth.UserID)
+	assert.False(t, hooks.decisions[1].Allowed)
+	assert.Equal(t, 403, hooks.decisions[1].Status)
+	assert.Error(t, hooks.decisions[1].Err)
+	assert.Equal(t, 401, hooks.decisions[2].Status)
+	assert.Nil(t, hooks.decisions[2].Auth)
+	assert.Equal(t, []int{200, 403, 401}, []int{hooks.responses[0].Status, hooks.responses[1].Status, hooks.responses[2].Status})
+}
+
+// partialHooks only implements OnResponse on top of NopHooks.
+type partialHooks struct {
+	NopHooks
+	statuses []int
+}
+
+func (h *partialHooks) OnResponse(c fiber.Ctx, op *OpenAPIOperation, r ResponseInfo) {
+	h.statuses = append(h.statuses, r.Status)
+}
+
+func TestHooks_NopHooksEmbedding(t *testing.T) {
+	hooks := &partialHooks{}
+	oapi := newTestApp(Config{Hooks: hooks})
+	Post(oapi, "/users/:name", hookedCreateUser, OpenAPIOptions{OperationID: "createUser"})
+	sendRequest(t, oapi, "POST", "/users/bob", `{}`)
+	sendRequest(t, oapi, "POST", "/users/a", `{}`)
+	assert.Equal(t, []int{200, 422}, hooks.statuses)
+}
+
+func TestHooks_OperationMiddlewareIncluded(t *testing.T) {
+	hooks := &recordingHooks{}
+	oapi := newTestApp(Config{Hooks: hooks})
+	Get(oapi, "/blocked", func(c fiber.Ctx, input struct{}) (uniOutput, error) {
+		return uniOutput{}, nil
+	}, OpenAPIOptions{OperationID: "blocked", Middleware: []fiber.Handler{func(c fiber.Ctx) error {
+		return fiber.ErrTeapot
+	}}})
+
+	resp := sendRequest(t, oapi, "GET", "/blocked", "")
+	require.Equal(t, 418, resp.StatusCode)
+	assert.Equal(t, []string{"start:blocked", "response"}, hooks.events)
+	assert.Equal(t, 418, hooks.responses[0].Status)
+	assert.ErrorIs(t, hooks.responses[0].Err, fiber.ErrTeapot)
+}
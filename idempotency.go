@@ -0,0 +1,389 @@
+package fiberoapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on replayed responses.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen = 255
+)
+
+// Entry types of the idempotency errors.
+const (
+	errTypeIdempotencyKeyMissing = "idempotency_key_missing"
+	errTypeIdempotencyKeyInvalid = "idempotency_key_invalid"
+	errTypeIdempotencyKeyInUse   = "idempotency_key_in_use"
+	errTypeIdempotencyKeyReused  = "idempotency_key_reused"
+)
+
+// Idempotency makes a POST or PATCH operation safe to retry. The first
+// response to a given Idempotency-Key is stored and replayed to the retries
+// carrying the same key and body, without running the handler again. Keys
+// are scoped to the operation, the request path and query, and the
+// authenticated user.
+//
+// Retries arriving while the first request is still running get a 409, and a
+// key reused with a different body a 422. 5xx responses are not stored, so a
+// failed request can be retried with the same key.
+type Idempotency struct {
+	Required bool          // Reject requests without an Idempotency-Key with 400 (default: the header is optional)
+	TTL      time.Duration // How long responses are kept (default: 24h)
+}
+
+func (i *Idempotency) ttl() time.Duration {
+	if i.TTL <= 0 {
+		return 24 * time.Hour
+	}
+	return i.TTL
+}
+
+// IdempotentResponse is a stored response.
+type IdempotentResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyRecord is what a store knows about a key.
+type IdempotencyRecord struct {
+	Fingerprint string              // SHA-256 of the request body of the first request
+	Response    *IdempotentResponse // nil while the first request is in flight
+}
+
+// IdempotencyStore holds the records of Idempotency-Key requests. Implement
+// it over a shared store (Redis...) to deduplicate retries across instances;
+// Reserve must then be atomic.
+type IdempotencyStore interface {
+	// Reserve marks key as in flight for a request with fingerprint, unless
+	// the key is already known: it then returns the existing record and
+	// reserved is false.
+	Reserve(key, fingerprint string, ttl time.Duration) (existing IdempotencyRecord, reserved bool, err error)
+	// Complete stores the response of the request that reserved key.
+	Complete(key string, resp IdempotentResponse, ttl time.Duration) error
+	// Release forgets key, so that the request can be retried.
+	Release(key string) error
+}
+
+// MemoryIdempotencyStore is the default in-process IdempotencyStore. Expired
+// records are dropped lazily.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	records   map[string]*idempotencyEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type idempotencyEntry struct {
+	record  IdempotencyRecord
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore returns an empty in-memory store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]*idempotencyEntry), now: time.Now}
+}
+
+// Reserve implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Reserve(key, fingerprint string, ttl time.Duration) (IdempotencyRecord, bool, error) {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	if e, ok := s.records[key]; ok && now.Before(e.expires) {
+		return e.record, false, nil
+	}
+	s.records[key] = &idempotencyEntry{record: IdempotencyRecord{Fingerprint: fingerprint}, expires: now.Add(ttl)}
+	return IdempotencyRecord{}, true, nil
+}
+
+// Complete implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Complete(key string, resp IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.records[key]
+	if !ok {
+		return fmt.Errorf("idempotency key %q is not reserved", key)
+	}
+	e.record.Response = &resp
+	e.expires = s.now().Add(ttl)
+	return nil
+}
+
+// Release implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// sweep drops the expired records, at most once a minute.
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.records {
+		if !now.Before(e.expires) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// validIdempotencyKey reports whether key is a printable ASCII token of at
+// most 255 characters.
+func validIdempotencyKey(key string) bool {
+	if len(key) > maxIdempotencyKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x21 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// unreplayedHeaders are response headers describing the current request
+// rather than the stored response. Cookies are never replayed: a session
+// set for the first caller must not be handed out again.
+var unreplayedHeaders = []string{
+	fiber.HeaderContentLength, fiber.HeaderDate, fiber.HeaderServer, fiber.HeaderRetryAfter, fiber.HeaderSetCookie,
+	"RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset",
+	requestIDHeader,
+}
+
+// serveIdempotent runs next, which answers the request, at most once per
+// Idempotency-Key: the response is stored and replayed to retries.
+func (o *OApiApp) serveIdempotent(c fiber.Ctx, op *OpenAPIOperation, next func() error) error {
+	opts := op.Options.Idempotency
+	key := c.Get(IdempotencyKeyHeader)
+	if key == "" {
+		if opts.Required {
+			return o.sendIdempotencyError(c, errIdempotencyKeyMissing)
+		}
+		return next()
+	}
+	if !validIdempotencyKey(key) {
+		return o.sendIdempotencyError(c, errIdempotencyKeyInvalid)
+	}
+
+	user := ""
+	if auth, ok := c.Locals("auth").(*AuthContext); ok && auth != nil {
+		user = auth.UserID
+	}
+	// The concrete path and query, not the route template: the same key sent
+	// to /payments/1 and /payments/2 names two different requests.
+	target := c.Path()
+	if q := c.Request().URI().QueryString(); len(q) > 0 {
+		target += "?" + string(q)
+	}
+	storeKey := op.Method + " " + target + " " + op.Version + "|" + user + "|" + key
+	sum := sha256.Sum256(c.Body())
+	fingerprint := hex.EncodeToString(sum[:])
+
+	existing, reserved, err := o.idempotency.Reserve(storeKey, fingerprint, opts.ttl())
+	if err != nil {
+		return o.sendIdempotencyError(c, errIdempotencyStoreUnavailable)
+	}
+	if !reserved {
+		switch {
+		case existing.Fingerprint != fingerprint:
+			return o.sendIdempotencyError(c, errIdempotencyKeyReused)
+		case existing.Response == nil:
+			return o.sendIdempotencyError(c, errIdempotencyKeyInUse)
+		}
+		return replayResponse(c, existing.Response)
+	}
+
+	// Anything but a stored response, including a panic, frees the key.
+	completed := false
+	defer func() {
+		if !completed {
+			_ = o.idempotency.Release(storeKey)
+		}
+	}()
+	if err := next(); err != nil {
+		return err
+	}
+	resp := c.Response()
+	if resp.StatusCode() >= fiber.StatusInternalServerError {
+		return nil
+	}
+	stored := IdempotentResponse{
+		Status: resp.StatusCode(),
+		Header: http.Header{},
+		Body:   bytes.Clone(resp.Body()),
+	}
+	for name, value := range resp.Header.All() {
+		stored.Header.Add(string(name), string(value))
+	}
+	for _, h := range unreplayedHeaders {
+		stored.Header.Del(h)
+	}
+	if err := o.idempotency.Complete(storeKey, stored, opts.ttl()); err == nil {
+		completed = true
+	}
+	return nil
+}
+
+// replayResponse writes a stored response.
+func replayResponse(c fiber.Ctx, stored *IdempotentResponse) error {
+	for name, values := range stored.Header {
+		if http.CanonicalHeaderKey(name) == fiber.HeaderSetCookie {
+			continue
+		}
+		c.Set(name, strings.Join(values, ", "))
+	}
+	c.Set(IdempotentReplayedHeader, "true")
+	return c.Status(stored.Status).Send(stored.Body)
+}
+
+// idempotencyError is an error answered about the Idempotency-Key of a
+// request.
+type idempotencyError struct {
+	status      int
+	errType     string
+	msg         string
+	description string // Of the response documenting it in the spec
+	keyLoc      bool   // Whether the entry points at the header
+}
+
+var (
+	errIdempotencyKeyMissing = idempotencyError{
+		status: fiber.StatusBadRequest, errType: errTypeIdempotencyKeyMissing, keyLoc: true,
+		msg:         "the Idempotency-Key header is required",
+		description: "Idempotency-Key header missing",
+	}
+	errIdempotencyKeyInvalid = idempotencyError{
+		status: fiber.StatusBadRequest, errType: errTypeIdempotencyKeyInvalid, keyLoc: true,
+		msg:         fmt.Sprintf("the Idempotency-Key header must be 1 to %d printable ASCII characters", maxIdempotencyKeyLen),
+		description: "Invalid Idempotency-Key header",
+	}
+	errIdempotencyKeyInUse = idempotencyError{
+		status: fiber.StatusConflict, errType: errTypeIdempotencyKeyInUse, keyLoc: true,
+		msg:         "a request with this Idempotency-Key is still being processed",
+		description: "A request with the same Idempotency-Key is in progress",
+	}
+	errIdempotencyKeyReused = idempotencyError{
+		status: fiber.StatusUnprocessableEntity, errType: errTypeIdempotencyKeyReused, keyLoc: true,
+		msg:         "this Idempotency-Key was already used with a different request body",
+		description: "Idempotency-Key reused with a different request body",
+	}
+	errIdempotencyStoreUnavailable = idempotencyError{
+		status: fiber.StatusServiceUnavailable, errType: statusErrorType(fiber.StatusServiceUnavailable),
+		msg:         "idempotency store unavailable; retry later",
+		description: "Idempotency store unavailable",
+	}
+)
+
+func (e idempotencyError) category() errorCategory {
+	return errorCategory{Code: e.status, Type: e.errType, Message: e.msg}
+}
+
+func (e idempotencyError) entry() ValidationErrorEntry {
+	entry := ValidationErrorEntry{Type: e.errType, Code: e.status, Loc: []any{}, Msg: e.msg}
+	if e.keyLoc {
+		entry.Loc = []any{"header", IdempotencyKeyHeader}
+		entry.Field = IdempotencyKeyHeader
+	}
+	return entry
+}
+
+func (e idempotencyError) exampleEnvelope() ErrorEnvelope {
+	return exampleEnvelope(e.entry())
+}
+
+// sendIdempotencyError writes an error about the Idempotency-Key header.
+func (o *OApiApp) sendIdempotencyError(c fiber.Ctx, e idempotencyError) error {
+	return o.sendError(c, e.status, e.category(), []ValidationErrorEntry{e.entry()})
+}
+
+// idempotencySpecParameter documents the Idempotency-Key header.
+func idempotencySpecParameter(opts *Idempotency) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     IdempotencyKeyHeader,
+		"in":       "header",
+		"required": opts.Required,
+		"description": fmt.Sprintf("Unique key making the request safe to retry: the first response is replayed "+
+			"to retries with the same key and body for %s.", opts.ttl()),
+		"schema": map[string]interface{}{"type": "string", "minLength": 1, "maxLength": maxIdempotencyKeyLen},
+	}
+}
+
+// addIdempotencyResponses documents the errors an idempotent operation can
+// answer. They share their status with other default errors (400 parse, 422
+// validation), so each is merged into the existing response as a named
+// example rather than replacing it.
+func addIdempotencyResponses(responses map[string]interface{}, opts *Idempotency, content func(errorCategory, func() ErrorEnvelope) map[string]interface{}) {
+	errs := []idempotencyError{errIdempotencyKeyInvalid, errIdempotencyKeyInUse, errIdempotencyKeyReused, errIdempotencyStoreUnavailable}
+	if opts.Required {
+		errs = append([]idempotencyError{errIdempotencyKeyMissing}, errs...)
+	}
+	for _, e := range errs {
+		mergeErrorResponse(responses, e.status, e.description, e.errType, content(e.category(), e.exampleEnvelope))
+	}
+}
+
+// mergeErrorResponse adds an error response to responses. When the status is
+// already documented, the descriptions are joined, differing schemas are listed
+// once in a oneOf and the examples are listed under examples, the new one as
+// name.
+func mergeErrorResponse(responses map[string]interface{}, status int, description, name string, content map[string]interface{}) {
+	key := statusCodeKey(status)
+	existing, ok := responses[key].(map[string]interface{})
+	if !ok {
+		responses[key] = map[string]interface{}{"description": description, "content": content}
+		return
+	}
+	existing["description"] = fmt.Sprintf("%v; %s", existing["description"], description)
+	existingContent, _ := existing["content"].(map[string]interface{})
+	if existingContent == nil {
+		existingContent = map[string]interface{}{}
+		existing["content"] = existingContent
+	}
+	for mediaType, raw := range content {
+		added := raw.(map[string]interface{})
+		current, ok := existingContent[mediaType].(map[string]interface{})
+		if !ok {
+			existingContent[mediaType] = added
+			continue
+		}
+		if !reflect.DeepEqual(current["schema"], added["schema"]) {
+			schemas, _ := current["schema"].(map[string]interface{})["oneOf"].([]interface{})
+			if schemas == nil {
+				schemas = []interface{}{current["schema"]}
+			}
+			if !slices.ContainsFunc(schemas, func(s interface{}) bool { return reflect.DeepEqual(s, added["schema"]) }) {
+				current["schema"] = map[string]interface{}{"oneOf": append(schemas, added["schema"])}
+			}
+		}
+		examples, _ := current["examples"].(map[string]interface{})
+		if examples == nil {
+			examples = map[string]interface{}{}
+			if example, ok := current["example"]; ok {
+				examples["default"] = map[string]interface{}{"value": example}
+				delete(current, "example")
+			}
+			current["examples"] = examples
+		}
+		examples[name] = map[string]interface{}{"value": added["example"]}
+	}
+}
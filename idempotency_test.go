@@ -0,0 +1,315 @@
+package fiberoapi
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paymentInput struct {
+	Amount int `json:"amount" validate:"required,min=1"`
+}
+
+type refundInput struct {
+	PaymentID int `uri:"id"`
+	Amount    int `json:"amount" validate:"required,min=1"`
+}
+
+type paymentOutput struct {
+	ID     int64 `json:"id"`
+	Amount int   `json:"amount"`
+}
+
+type paymentDeclined struct {
+	StatusCode int    `json:"-"`
+	Reason     string `json:"reason"`
+}
+
+func (e *paymentDeclined) Error() string { return e.Reason }
+
+// payments counts handler calls. An amount of 13 fails with a 500, 402 is
+// declined and 99 blocks until release is closed.
+type payments struct {
+	calls   atomic.Int64
+	release chan struct{}
+}
+
+func (p *payments) create(c fiber.Ctx, in paymentInput) (paymentOutput, error) {
+	n := p.calls.Add(1)
+	switch in.Amount {
+	case 13:
+		return paymentOutput{}, errors.New("ledger unavailable")
+	case 402:
+		return paymentOutput{}, &paymentDeclined{StatusCode: 402, Reason: "insufficient funds"}
+	case 99:
+		<-p.release
+	}
+	c.Set(fiber.HeaderLocation, "/payments/1")
+	c.Cookie(&fiber.Cookie{Name: "session", Value: "s3cr3t"})
+	c.Status(fiber.StatusCreated)
+	return paymentOutput{ID: n, Amount: in.Amount}, nil
+}
+
+func (p *payments) refund(c fiber.Ctx, in refundInput) (paymentOutput, error) {
+	p.calls.Add(1)
+	return paymentOutput{ID: int64(in.PaymentID), Amount: in.Amount}, nil
+}
+
+func TestIdempotency_ReplaysFirstResponse(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+
+	first := sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k-1")
+	require.Equal(t, 201, first.StatusCode)
+	assert.Empty(t, first.Header.Get(IdempotentReplayedHeader))
+
+	retry := sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k-1")
+	require.Equal(t, 201, retry.StatusCode)
+	assert.Equal(t, first.body, retry.body)
+	assert.Equal(t, "true", retry.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, "/payments/1", retry.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, first.Header.Get(fiber.HeaderContentType), retry.Header.Get(fiber.HeaderContentType))
+	assert.NotEmpty(t, first.Header.Get(fiber.HeaderSetCookie))
+	assert.Empty(t, retry.Header.Get(fiber.HeaderSetCookie), "cookies are never replayed")
+	assert.Equal(t, int64(1), p.calls.Load())
+
+	// Another key is another payment.
+	other := sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k-2")
+	assert.NotEqual(t, first.body, other.body)
+	assert.Equal(t, int64(2), p.calls.Load())
+}
+
+func TestIdempotency_ReplaysClientErrors(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+	first := sendRequest(t, oapi, "POST", "/payments", `{"amount":402}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 402, first.StatusCode)
+	retry := sendRequest(t, oapi, "POST", "/payments", `{"amount":402}`, IdempotencyKeyHeader, "k")
+	assert.Equal(t, 402, retry.StatusCode)
+	assert.Equal(t, first.body, retry.body)
+	assert.Equal(t, int64(1), p.calls.Load())
+}
+
+func TestIdempotency_ServerErrorsAreNotStored(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+	first := sendRequest(t, oapi, "POST", "/payments", `{"amount":13}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 500, first.StatusCode)
+	retry := sendRequest(t, oapi, "POST", "/payments", `{"amount":13}`, IdempotencyKeyHeader, "k")
+	assert.Equal(t, 500, retry.StatusCode)
+	assert.Empty(t, retry.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, int64(2), p.calls.Load())
+}
+
+func TestIdempotency_DifferentBodyIs422(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+	sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k")
+	resp := sendRequest(t, oapi, "POST", "/payments", `{"amount":11}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 422, resp.StatusCode)
+
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, errTypeIdempotencyKeyReused, env.Errors[0].Type)
+	assert.Equal(t, []any{"header", IdempotencyKeyHeader}, env.Errors[0].Loc)
+	assert.Equal(t, int64(1), p.calls.Load())
+}
+
+func TestIdempotency_InFlightDuplicateIs409(t *testing.T) {
+	release := make(chan struct{})
+	p := &payments{release: release}
+	oapi := newTestApp()
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+
+	done := make(chan int)
+	go func() {
+		resp := sendRequest(t, oapi, "POST", "/payments", `{"amount":99}`, IdempotencyKeyHeader, "k")
+		done <- resp.StatusCode
+	}()
+	require.Eventually(t, func() bool { return p.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	resp := sendRequest(t, oapi, "POST", "/payments", `{"amount":99}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 409, resp.StatusCode)
+	assert.Contains(t, string(resp.body), errTypeIdempotencyKeyInUse)
+
+	close(release)
+	assert.Equal(t, 201, <-done)
+	resp = sendRequest(t, oapi, "POST", "/payments", `{"amount":99}`, IdempotencyKeyHeader, "k")
+	assert.Equal(t, 201, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get(IdempotentReplayedHeader))
+}
+
+func TestIdempotency_KeysAreScopedToTheUser(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	asUser := func(c fiber.Ctx) error {
+		if user := c.Get("X-User"); user != "" {
+			c.Locals("auth", &AuthContext{UserID: user})
+		}
+		return c.Next()
+	}
+	Post(oapi, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}, Middleware: []fiber.Handler{asUser}})
+	sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k", "X-User", "alice")
+	resp := sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k", "X-User", "bob")
+	assert.Empty(t, resp.Header.Get(IdempotentReplayedHeader))
+	resp = sendRequest(t, oapi, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, "k", "X-User", "alice")
+	assert.Equal(t, "true", resp.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, int64(2), p.calls.Load())
+}
+
+func TestIdempotency_KeysAreScopedToThePath(t *testing.T) {
+	p := &payments{}
+	oapi := newTestApp()
+	Post(oapi, "/payments/:id/refunds", p.refund, OpenAPIOptions{Idempotency: &Idempotency{}})
+
+	first := sendRequest(t, oapi, "POST", "/payments/1/refunds", `{"amount":10}`, IdempotencyKeyHeader, "k")
+	resp := sendRequest(t, oapi, "POST", "/payments/2/refunds", `{"amount":10}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(IdempotentReplayedHeader))
+	assert.NotEqual(t, first.body, resp.body)
+	assert.Contains(t, string(resp.body), `"id":2`)
+
+	resp = sendRequest(t, oapi, "POST", "/payments/2/refunds?reason=dup", `{"amount":10}`, IdempotencyKeyHeader, "k")
+	assert.Empty(t, resp.Header.Get(IdempotentReplayedHeader), "the query is part of the scope")
+
+	resp = sendRequest(t, oapi, "POST", "/payments/1/refunds", `{"amount":10}`, IdempotencyKeyHeader, "k")
+	assert.Equal(t, "true", resp.Header.Get(IdempotentReplayedHeader))
+	assert.Equal(t, int64(3), p.calls.Load())
+}
+
+func TestIdempotency_MissingAndInvalidKeys(t *testing.T) {
+	p := &payments{}
+	optional := newTestApp()
+	Post(optional, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{}})
+	sendRequest(t, optional, "POST", "/payments", `{"amount":10}`)
+	sendRequest(t, optional, "POST", "/payments", `{"amount":10}`)
+	assert.Equal(t, int64(2), p.calls.Load(), "without a key every request runs")
+
+	p = &payments{}
+	required := newTestApp()
+	Post(required, "/payments", p.create, OpenAPIOptions{Idempotency: &Idempotency{Required: true}})
+	resp := sendRequest(t, required, "POST", "/payments", `{"amount":10}`)
+	require.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, string(resp.body), errTypeIdempotencyKeyMissing)
+
+	resp = sendRequest(t, required, "POST", "/payments", `{"amount":10}`, IdempotencyKeyHeader, strings.Repeat("k", 256))
+	require.Equal(t, 400, resp.StatusCode)
+	assert.Contains(t, string(resp.body), errTypeIdempotencyKeyInvalid)
+	assert.Zero(t, p.calls.Load())
+
+	// Invalid input is rejected before the key is reserved.
+	resp = sendRequest(t, required, "POST", "/payments", `{"amount":0}`, IdempotencyKeyHeader, "k")
+	require.Equal(t, 422, resp.StatusCode)
+	resp = sendRequest(t, required, "POST", "/payments", `{"amount":5}`, IdempotencyKeyHeader, "k")
+	assert.Equal(t, 201, resp.StatusCode)
+}
+
+func TestMemoryIdempotencyStore_Expiry(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryIdempotencyStore()
+	store.now = clock.now
+
+	_, reserved, err := store.Reserve("k", "fp", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+	require.NoError(t, store.Complete("k", IdempotentResponse{Status: 201, Body: []byte("{}")}, time.Hour))
+
+	rec, reserved, _ := store.Reserve("k", "fp", time.Hour)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, rec.Response.Status)
+
+	clock.t = clock.t.Add(time.Hour)
+	_, reserved, _ = store.Reserve("k", "fp", time.Hour)
+	assert.True(t, reserved)
+
+	assert.Error(t, store.Complete("unknown", IdempotentResponse{}, time.Hour))
+}
+
+func TestIdempotency_Spec(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/payments", func(c fiber.Ctx, in paymentInput) (paymentOutput, error) {
+		return paymentOutput{}, nil
+	}, OpenAPIOptions{Idempotency: &Idempotency{Required: true, TTL: time.Hour}})
+
+	spec := oapi.GenerateOpenAPISpec()
+	op := spec["paths"].(map[string]interface{})["/payments"].(map[string]interface{})["post"].(map[string]interface{})
+
+	var header map[string]interface{}
+	for _, p := range op["parameters"].([]map[string]interface{}) {
+		if p["name"] == IdempotencyKeyHeader {
+			header = p
+		}
+	}
+	require.NotNil(t, header)
+	assert.Equal(t, "header", header["in"])
+	assert.Equal(t, true, header["required"])
+	assert.Contains(t, header["description"], "1h0m0s")
+
+	responses := op["responses"].(map[string]interface{})
+	resp := responses["409"].(map[string]interface{})
+	example := resp["content"].(map[string]interface{})["application/json"].(map[string]interface{})["example"].(ErrorEnvelope)
+	assert.Equal(t, errTypeIdempotencyKeyInUse, example.Errors[0].Type)
+
+	resp = responses["503"].(map[string]interface{})
+	assert.Equal(t, "Idempotency store unavailable", resp["description"])
+
+	// 400 and 422 keep the parse / validation docs and list the
+	// Idempotency-Key errors as further examples.
+	for status, types := range map[string][]string{
+		"400": {errTypeIdempotencyKeyMissing, errTypeIdempotencyKeyInvalid},
+		"422": {errTypeIdempotencyKeyReused},
+	} {
+		resp = responses[status].(map[string]interface{})
+		assert.Contains(t, resp["description"], "Idempotency-Key", status)
+		content := resp["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+		assert.NotContains(t, content, "example", status)
+		examples := content["examples"].(map[string]interface{})
+		assert.Contains(t, examples, "default", status)
+		for _, typ := range types {
+			require.Contains(t, examples, typ, status)
+			value := examples[typ].(map[string]interface{})["value"].(ErrorEnvelope)
+			assert.Equal(t, typ, value.Errors[0].Type)
+		}
+	}
+}
+
+type paymentRejected struct {
+	Reason string `json:"reason"`
+}
+
+func (paymentRejected) HTTPStatus() int { return 400 }
+
+func TestIdempotency_SpecMergesDeclaredErrors(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/payments", func(c fiber.Ctx, in paymentInput) (paymentOutput, error) {
+		return paymentOutput{}, nil
+	}, OpenAPIOptions{Idempotency: &Idempotency{Required: true}, Errors: []any{clientGenConflict{}, paymentRejected{}}})
+
+	responses := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/payments"].(map[string]interface{})["post"].(map[string]interface{})["responses"].(map[string]interface{})
+	envelope := map[string]interface{}{"$ref": "#/components/schemas/ErrorEnvelope"}
+	for status, declared := range map[string]string{"409": "clientGenConflict", "400": "paymentRejected"} {
+		media := responses[status].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+		schemas := media["schema"].(map[string]interface{})["oneOf"].([]interface{})
+		// The 400 merges two Idempotency-Key errors, listed once in oneOf.
+		assert.Equal(t, []interface{}{map[string]interface{}{"$ref": "#/components/schemas/" + declared}, envelope}, schemas, status)
+	}
+}
+
+func TestIdempotency_UnsafeMethodsOnly(t *testing.T) {
+	oapi := newTestApp()
+	assert.Panics(t, func() {
+		Get(oapi, "/payments", func(c fiber.Ctx, in struct{}) (paymentOutput, error) {
+			return paymentOutput{}, nil
+		}, OpenAPIOptions{Idempotency: &Idempotency{}})
+	})
+}
@@ -0,0 +1,109 @@
+package fiberoapi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// InputValidator is implemented by input types with rules `validate` tags
+// cannot express: cross-field checks (end after start, email or phone) and
+// checks that need the request. parseInput calls Validate after the tags
+// passed, with value or pointer receivers alike.
+//
+// Return ValidationIssues to point at fields; any other error becomes a single
+// body-level entry. Either way the response is the same 422 as tag failures.
+type InputValidator interface {
+	Validate(c fiber.Ctx) error
+}
+
+// ValidationIssue is one failure reported by an InputValidator.
+type ValidationIssue struct {
+	Field   string // Go field path from the input root, as in validator namespaces: "EndDate", "Address.Zipcode"; empty for the whole body
+	Rule    string // Reported as the entry constraint and used to look up the message, e.g. "gtfield"
+	Param   string // Rule parameter, e.g. "StartDate"
+	Message string // Optional; rendered from the message catalogue (Rule, Param) when empty
+}
+
+// ValidationIssues is the error an InputValidator returns to report
+// field-level failures:
+//
+//	func (in BookingInput) Validate(c fiber.Ctx) error {
+//		var issues fiberoapi.ValidationIssues
+//		if !in.End.After(in.Start) {
+//			issues = append(issues, fiberoapi.ValidationIssue{Field: "End", Rule: "gtfield", Param: "start"})
+//		}
+//		if in.Email == "" && in.Phone == "" {
+//			issues = append(issues, fiberoapi.ValidationIssue{Field: "Email", Message: "email or phone is required"})
+//		}
+//		return issues.Err()
+//	}
+type ValidationIssues []ValidationIssue
+
+func (v ValidationIssues) Error() string {
+	msgs := make([]string, len(v))
+	for i, issue := range v {
+		msgs[i] = issue.Message
+		if msgs[i] == "" {
+			msgs[i] = strings.TrimPrefix(issue.Field+": "+constraintString(issue.Rule, issue.Param), ": ")
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Err returns v as an error, or nil when there are no issues, so a Validate
+// method can end with `return issues.Err()`.
+func (v ValidationIssues) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return v
+}
+
+// runInputValidator calls the input's Validate method, if any, and normalises
+// its result to ValidationIssues.
+func runInputValidator(c fiber.Ctx, input any) error {
+	v, ok := input.(InputValidator)
+	if !ok {
+		return nil
+	}
+	err := v.Validate(c)
+	if err == nil {
+		return nil
+	}
+	if issues, ok := errors.AsType[ValidationIssues](err); ok {
+		if len(issues) == 0 {
+			return nil
+		}
+		return issues
+	}
+	return ValidationIssues{{Message: err.Error()}}
+}
+
+// issueEntries converts ValidationIssues to envelope entries, resolving each
+// field path to its loc like a tag failure.
+func issueEntries(msg messageFunc, resolver *locResolver, issues ValidationIssues) []ValidationErrorEntry {
+	entries := make([]ValidationErrorEntry, 0, len(issues))
+	for _, issue := range issues {
+		loc, leaf := []any{"body"}, ""
+		if issue.Field != "" {
+			if resolved, l := resolver.resolve(issue.Field); len(resolved) > 0 {
+				loc, leaf = resolved, l
+			}
+		}
+		text := issue.Message
+		if text == "" {
+			text = msg(leaf, issue.Rule, issue.Param)
+		}
+		entries = append(entries, ValidationErrorEntry{
+			Type:       errTypeValidation,
+			Code:       statusValidationError,
+			Loc:        loc,
+			Field:      leaf,
+			Msg:        text,
+			Constraint: constraintString(issue.Rule, issue.Param),
+		})
+	}
+	return entries
+}
@@ -0,0 +1,147 @@
+package fiberoapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bookingAddress struct {
+	Zipcode string `json:"zipcode"`
+}
+
+type bookingInput struct {
+	Room    string         `uri:"room" validate:"required"`
+	Start   time.Time      `json:"start" validate:"required"`
+	End     time.Time      `json:"end" validate:"required"`
+	Email   string         `json:"email"`
+	Phone   string         `json:"phone"`
+	Address bookingAddress `json:"address"`
+}
+
+func (in bookingInput) Validate(c fiber.Ctx) error {
+	var issues ValidationIssues
+	if !in.End.After(in.Start) {
+		issues = append(issues, ValidationIssue{Field: "End", Rule: "gtfield", Param: "start"})
+	}
+	if in.Email == "" && in.Phone == "" {
+		issues = append(issues, ValidationIssue{Field: "Email", Rule: "required_without", Message: "email or phone is required"})
+	}
+	if in.Address.Zipcode == "00000" {
+		issues = append(issues, ValidationIssue{Field: "Address.Zipcode", Rule: "zipcode", Message: "unknown zipcode"})
+	}
+	if in.Room == "closed" && c.Get("X-Override") == "" {
+		issues = append(issues, ValidationIssue{Field: "Room", Rule: "open", Message: "room is closed"})
+	}
+	return issues.Err()
+}
+
+// pointerInput validates through a pointer receiver and returns a plain error.
+type pointerInput struct {
+	Name string `json:"name"`
+}
+
+func (in *pointerInput) Validate(c fiber.Ctx) error {
+	if in.Name == "forbidden" {
+		return errors.New("this name is reserved")
+	}
+	return nil
+}
+
+func bookRoom(c fiber.Ctx, in bookingInput) (uniOutput, error) {
+	return uniOutput{Message: "booked"}, nil
+}
+
+func createName(c fiber.Ctx, in pointerInput) (uniOutput, error) {
+	return uniOutput{Message: in.Name}, nil
+}
+
+func TestInputValidator_Passes(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/rooms/:room/bookings", bookRoom, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/rooms/a/bookings", `{"start":"2026-01-01T10:00:00Z","end":"2026-01-01T11:00:00Z","email":"a@b.c"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestInputValidator_IssuesBecomeEntries(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/rooms/:room/bookings", bookRoom, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/rooms/closed/bookings", `{"start":"2026-01-01T10:00:00Z","end":"2026-01-01T09:00:00Z","address":{"zipcode":"00000"}}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 4)
+
+	end := env.Errors[0]
+	assert.Equal(t, "validation_error", end.Type)
+	assert.Equal(t, 422, end.Code)
+	assert.Equal(t, []any{"body", "end"}, end.Loc)
+	assert.Equal(t, "end", end.Field)
+	assert.Equal(t, "gtfield=start", end.Constraint)
+	assert.Equal(t, "field 'end' failed validation: gtfield=start", end.Msg)
+
+	assert.Equal(t, []any{"body", "email"}, env.Errors[1].Loc)
+	assert.Equal(t, "email or phone is required", env.Errors[1].Msg)
+	assert.Equal(t, []any{"body", "address", "zipcode"}, env.Errors[2].Loc)
+	assert.Equal(t, []any{"path", "room"}, env.Errors[3].Loc)
+}
+
+func TestInputValidator_RunsAfterTags(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/rooms/:room/bookings", bookRoom, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/rooms/a/bookings", `{"end":"2026-01-01T09:00:00Z"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "required", env.Errors[0].Constraint)
+}
+
+func TestInputValidator_PointerReceiverAndPlainError(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/names", createName, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/names", `{"name":"forbidden"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, []any{"body"}, env.Errors[0].Loc)
+	assert.Equal(t, "this name is reserved", env.Errors[0].Msg)
+
+	resp = sendRequest(t, oapi, "POST", "/names", `{"name":"fine"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestInputValidator_MessagesFromCatalogue(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{}})
+	Post(oapi, "/rooms/:room/bookings", bookRoom, OpenAPIOptions{})
+	oapi.RegisterMessages("fr", MessageCatalog{"gtfield": "le champ '{field}' doit être postérieur à '{param}'"})
+	resp := sendRequest(t, oapi, "POST", "/rooms/a/bookings", `{"start":"2026-01-01T10:00:00Z","end":"2026-01-01T09:00:00Z","email":"a@b.c"}`, "Accept-Language", "fr")
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "le champ 'end' doit être postérieur à 'start'", env.Errors[0].Msg)
+}
+
+func TestInputValidator_KeepsEnvelopeUnderDefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{DefaultErrorShape: &UniErr{}})
+	Post(oapi, "/names", createName, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/names", `{"name":"forbidden"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+}
+
+func TestInputValidator_SkippedWhenValidationDisabled(t *testing.T) {
+	// A core signal field is needed for the zero EnableValidation to count.
+	oapi := newTestApp(Config{EnableOpenAPIDocs: true, SecuritySchemes: map[string]SecurityScheme{}})
+	Post(oapi, "/names", createName, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/names", `{"name":"forbidden"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestValidationIssues_Error(t *testing.T) {
+	issues := ValidationIssues{{Field: "End", Rule: "gtfield", Param: "Start"}, {Message: "email or phone is required"}}
+	assert.Equal(t, "End: gtfield=Start; email or phone is required", issues.Error())
+	assert.NoError(t, ValidationIssues(nil).Err())
+}
@@ -0,0 +1,261 @@
+package fiberoapi
+
+import (
+	"maps"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// MessageCatalog holds the validation message templates of one locale, keyed
+// by validator tag ("min") or, to override a single field, by field and tag
+// ("email.required"). Templates may use the {field}, {param} and {constraint}
+// placeholders; the "*" entry renders tags the catalogue does not list.
+type MessageCatalog map[string]string
+
+// catalogFallback is the MessageCatalog key used for unlisted tags.
+const catalogFallback = "*"
+
+// defaultLocale is the locale of the built-in English catalogue.
+const defaultLocale = "en"
+
+// LocaleResolver returns the locale of a request's validation messages, e.g.
+// from a user preference stored in Locals. An empty result selects
+// Localization.DefaultLocale.
+type LocaleResolver func(c fiber.Ctx) string
+
+// Localization turns on per-request validation messages. Without it every
+// message uses the default English catalogue.
+type Localization struct {
+	DefaultLocale string                    // Locale used when the request matches no catalogue (default: "en")
+	Catalogs      map[string]MessageCatalog // Extra locales, or entries merged over the built-in "en" and "fr"
+	Resolver      LocaleResolver            // Picks the locale (default: the Accept-Language header)
+}
+
+var builtinCatalogs = map[string]MessageCatalog{
+	"en": {
+		"required":      "field '{field}' is required",
+		"min":           "field '{field}' must be at least {param}",
+		"max":           "field '{field}' must be at most {param}",
+		"len":           "field '{field}' must be exactly {param}",
+		"email":         "field '{field}' must be a valid email address",
+		"url":           "field '{field}' must be a valid URL",
+		"uuid":          "field '{field}' must be a valid UUID",
+		"uuid4":         "field '{field}' must be a valid UUID",
+		"alphanum":      "field '{field}' must contain only alphanumeric characters",
+		"alpha":         "field '{field}' must contain only alphabetic characters",
+		"numeric":       "field '{field}' must be numeric",
+		"oneof":         "field '{field}' must be one of: {param}",
+		"gte":           "field '{field}' must be greater than or equal to {param}",
+		"lte":           "field '{field}' must be less than or equal to {param}",
+		"gt":            "field '{field}' must be greater than {param}",
+		"lt":            "field '{field}' must be less than {param}",
+		catalogFallback: "field '{field}' failed validation: {constraint}",
+	},
+	"fr": {
+		"required":      "le champ '{field}' est obligatoire",
+		"min":           "le champ '{field}' doit valoir au moins {param}",
+		"max":           "le champ '{field}' doit valoir au plus {param}",
+		"len":           "le champ '{field}' doit valoir exactement {param}",
+		"email":         "le champ '{field}' doit être une adresse e-mail valide",
+		"url":           "le champ '{field}' doit être une URL valide",
+		"uuid":          "le champ '{field}' doit être un UUID valide",
+		"uuid4":         "le champ '{field}' doit être un UUID valide",
+		"alphanum":      "le champ '{field}' ne doit contenir que des caractères alphanumériques",
+		"alpha":         "le champ '{field}' ne doit contenir que des lettres",
+		"numeric":       "le champ '{field}' doit être numérique",
+		"oneof":         "le champ '{field}' doit valoir l'une des valeurs suivantes : {param}",
+		"gte":           "le champ '{field}' doit être supérieur ou égal à {param}",
+		"lte":           "le champ '{field}' doit être inférieur ou égal à {param}",
+		"gt":            "le champ '{field}' doit être strictement supérieur à {param}",
+		"lt":            "le champ '{field}' doit être strictement inférieur à {param}",
+		catalogFallback: "le champ '{field}' n'est pas valide : {constraint}",
+	},
+}
+
+// messageFunc renders the message of one failed validator tag.
+type messageFunc func(field, tag, param string) string
+
+// newMessageCatalogs returns the built-in catalogues with the configured ones
+// merged over them. Locales are keyed in lower case.
+func newMessageCatalogs(l *Localization) map[string]MessageCatalog {
+	catalogs := make(map[string]MessageCatalog, len(builtinCatalogs))
+	for locale, catalog := range builtinCatalogs {
+		catalogs[locale] = maps.Clone(catalog)
+	}
+	if l != nil {
+		for locale, catalog := range l.Catalogs {
+			mergeCatalog(catalogs, locale, catalog)
+		}
+	}
+	return catalogs
+}
+
+func mergeCatalog(catalogs map[string]MessageCatalog, locale string, catalog MessageCatalog) {
+	locale = strings.ToLower(locale)
+	if catalogs[locale] == nil {
+		catalogs[locale] = MessageCatalog{}
+	}
+	maps.Copy(catalogs[locale], catalog)
+}
+
+// RegisterMessages adds a locale, or merges entries over an existing one:
+//
+//	oapi.RegisterMessages("de", fiberoapi.MessageCatalog{
+//		"required": "Feld '{field}' ist erforderlich",
+//		"*":        "Feld '{field}' ist ungültig: {constraint}",
+//	})
+//	oapi.RegisterMessages("en", fiberoapi.MessageCatalog{"email.required": "we need your email"})
+//
+// Locales other than the default are only served when Config.Localization is
+// set. Call it before serving requests.
+func (o *OApiApp) RegisterMessages(locale string, catalog MessageCatalog) {
+	if o.catalogs == nil {
+		o.catalogs = newMessageCatalogs(o.config.Localization)
+	}
+	mergeCatalog(o.catalogs, locale, catalog)
+}
+
+// defaultMessageLocale returns the configured default locale.
+func (o *OApiApp) defaultMessageLocale() string {
+	if l := o.config.Localization; l != nil && l.DefaultLocale != "" {
+		return strings.ToLower(l.DefaultLocale)
+	}
+	return defaultLocale
+}
+
+// requestLocale resolves the locale of a request: the custom resolver, then
+// Accept-Language, then the default locale.
+func (o *OApiApp) requestLocale(c fiber.Ctx) string {
+	l := o.config.Localization
+	if l == nil {
+		return o.defaultMessageLocale()
+	}
+	if l.Resolver != nil {
+		if locale := o.matchLocale(l.Resolver(c)); locale != "" {
+			return locale
+		}
+		return o.defaultMessageLocale()
+	}
+	for _, tag := range acceptedLanguages(c.Get(fiber.HeaderAcceptLanguage)) {
+		if locale := o.matchLocale(tag); locale != "" {
+			return locale
+		}
+	}
+	return o.defaultMessageLocale()
+}
+
+// matchLocale returns the catalogue matching a language tag exactly or by its
+// primary subtag ("fr-CA" → "fr"), or "" when there is none.
+func (o *OApiApp) matchLocale(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return ""
+	}
+	if _, ok := o.messageCatalogs()[tag]; ok {
+		return tag
+	}
+	primary, _, _ := strings.Cut(tag, "-")
+	if _, ok := o.messageCatalogs()[primary]; ok {
+		return primary
+	}
+	return ""
+}
+
+// acceptedLanguages returns the language tags of an Accept-Language header by
+// decreasing quality, skipping "*" and tags with q=0.
+func acceptedLanguages(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var langs []weighted
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+		langs = append(langs, weighted{tag, q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	tags := make([]string, len(langs))
+	for i, l := range langs {
+		tags[i] = l.tag
+	}
+	return tags
+}
+
+func (o *OApiApp) messageCatalogs() map[string]MessageCatalog {
+	if o.catalogs == nil {
+		return builtinCatalogs
+	}
+	return o.catalogs
+}
+
+// messagesFor returns the message renderer of a locale. Entries missing from
+// it fall back to the default locale, then to English.
+func (o *OApiApp) messagesFor(locale string) messageFunc {
+	catalogs := o.messageCatalogs()
+	chain := []MessageCatalog{catalogs[locale]}
+	for _, l := range []string{o.defaultMessageLocale(), defaultLocale} {
+		if l != locale {
+			chain = append(chain, catalogs[l])
+		}
+	}
+	return func(field, tag, param string) string {
+		return renderMessage(chain, field, tag, param)
+	}
+}
+
+// requestMessages returns the message renderer of a request's locale.
+func (o *OApiApp) requestMessages(c fiber.Ctx) messageFunc {
+	return o.messagesFor(o.requestLocale(c))
+}
+
+// specMessages returns the renderer used for spec examples: the default locale.
+func (o *OApiApp) specMessages() messageFunc {
+	return o.messagesFor(o.defaultMessageLocale())
+}
+
+// renderMessage fills the first template found along the chain. In each
+// catalogue the per-field entry wins over the tag entry, itself winning over
+// the "*" fallback, so a locale with a fallback never shows another language.
+func renderMessage(chain []MessageCatalog, field, tag, param string) string {
+	template := ""
+	for _, catalog := range chain {
+		if t, ok := lookupTemplate(catalog, field, tag); ok {
+			template = t
+			break
+		}
+	}
+	return strings.NewReplacer(
+		"{field}", field,
+		"{param}", param,
+		"{constraint}", constraintString(tag, param),
+	).Replace(template)
+}
+
+func lookupTemplate(catalog MessageCatalog, field, tag string) (string, bool) {
+	if t, ok := catalog[field+"."+tag]; ok {
+		return t, true
+	}
+	if t, ok := catalog[tag]; ok {
+		return t, true
+	}
+	t, ok := catalog[catalogFallback]
+	return t, ok
+}
@@ -0,0 +1,102 @@
+package fiberoapi
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createUniUser(c fiber.Ctx, in uniInput) (uniOutput, error) {
+	return uniOutput{Message: "ok"}, nil
+}
+
+// validationMsg posts an invalid name (min=2) and returns the entry message.
+func validationMsg(t *testing.T, oapi *OApiApp, acceptLanguage string) string {
+	t.Helper()
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{}`, "Accept-Language", acceptLanguage)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	return env.Errors[0].Msg
+}
+
+func TestMessages_DisabledKeepsEnglish(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+	assert.Equal(t, "field 'name' must be at least 2", validationMsg(t, oapi, "fr"))
+}
+
+func TestMessages_AcceptLanguage(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{}})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+
+	cases := []struct {
+		header string
+		msg    string
+	}{
+		{"", "field 'name' must be at least 2"},
+		{"fr", "le champ 'name' doit valoir au moins 2"},
+		{"fr-CA, en;q=0.5", "le champ 'name' doit valoir au moins 2"},
+		{"en;q=0.4, fr;q=0.9", "le champ 'name' doit valoir au moins 2"},
+		{"de-DE, en;q=0.8", "field 'name' must be at least 2"},
+		{"de", "field 'name' must be at least 2"},
+		{"fr;q=0, en", "field 'name' must be at least 2"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.header, func(t *testing.T) {
+			assert.Equal(t, tc.msg, validationMsg(t, oapi, tc.header))
+		})
+	}
+}
+
+func TestMessages_DefaultLocaleAndResolver(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{DefaultLocale: "fr"}})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+	assert.Equal(t, "le champ 'name' doit valoir au moins 2", validationMsg(t, oapi, "de"))
+	assert.Equal(t, "field 'name' must be at least 2", validationMsg(t, oapi, "en-GB"))
+
+	oapi = newTestApp(Config{Localization: &Localization{
+		Resolver: func(c fiber.Ctx) string { return c.Query("lang") },
+	}})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a?lang=fr", `{}`, "Accept-Language", "en")
+	assert.Contains(t, string(resp.body), "le champ 'name' doit valoir au moins 2")
+}
+
+func TestMessages_RegisteredCatalogues(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{
+		Catalogs: map[string]MessageCatalog{"fr": {"name.min": "le nom est trop court"}},
+	}})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+	oapi.RegisterMessages("de", MessageCatalog{"required": "Feld '{field}' ist erforderlich"})
+	oapi.RegisterMessages("es", MessageCatalog{"*": "campo '{field}' no es válido: {constraint}"})
+	oapi.RegisterMessages("en", MessageCatalog{"min": "'{field}' is shorter than {param}"})
+
+	assert.Equal(t, "le nom est trop court", validationMsg(t, oapi, "fr"))
+	// Entries a catalogue lacks come from the default locale...
+	assert.Equal(t, "'name' is shorter than 2", validationMsg(t, oapi, "de"))
+	// ...unless it has its own fallback.
+	assert.Equal(t, "campo 'name' no es válido: min=2", validationMsg(t, oapi, "es"))
+}
+
+func TestMessages_SpecExampleUsesDefaultLocale(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{DefaultLocale: "fr"}})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{})
+	op := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/users/{name}"].(map[string]interface{})["post"].(map[string]interface{})
+	content := op["responses"].(map[string]interface{})["422"].(map[string]interface{})["content"].(map[string]interface{})
+	example := content["application/json"].(map[string]interface{})["example"].(ErrorEnvelope)
+	assert.Equal(t, "le champ 'workspaceId' doit valoir au moins 11", example.Errors[0].Msg)
+}
+
+func TestAcceptedLanguages(t *testing.T) {
+	assert.Equal(t, []string{"fr-CH", "fr", "en", "de"}, acceptedLanguages("fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5"))
+	assert.Equal(t, []string{"en", "fr"}, acceptedLanguages("fr;q=0.5,en,xx;q=bad"))
+	assert.Empty(t, acceptedLanguages(""))
+}
+
+func TestTranslateValidatorTag_Fallback(t *testing.T) {
+	assert.Equal(t, "field 'code' failed validation: hexcolor", translateValidatorTag("code", "hexcolor", ""))
+	assert.Equal(t, "field 'code' failed validation: startswith=ab", translateValidatorTag("code", "startswith", "ab"))
+}
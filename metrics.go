@@ -0,0 +1,213 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// errorTypeLocalsKey is the fiber.Ctx locals key holding the entry type of
+// the library error answered to the request, when Config.Metrics is set.
+const errorTypeLocalsKey = "fiberoapi.errortype"
+
+// metricsContentType is the media type of the Prometheus text format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultMetricsBuckets are the upper bounds, in seconds, of the latency
+// histogram buckets when Metrics.Buckets is empty.
+var DefaultMetricsBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics configures the built-in per-operation metrics.
+type Metrics struct {
+	Path      string    // Route serving the metrics (default: "/metrics"); "-" to mount MetricsHandler yourself
+	Namespace string    // Metric name prefix (default: "fiberoapi")
+	Buckets   []float64 // Latency histogram upper bounds in seconds (default: DefaultMetricsBuckets)
+}
+
+// metricsKey identifies the series of one operation.
+type metricsKey struct {
+	operationID string
+	method      string
+	route       string
+}
+
+// operationMetrics holds the series of one operation.
+type operationMetrics struct {
+	statuses map[int]uint64
+	errors   map[string]uint64
+	buckets  []uint64 // per bucket, not cumulative
+	sum      float64
+	count    uint64
+}
+
+// metricsCollector aggregates the requests served by the operations of an
+// app, labelled by operationId, method and route template.
+type metricsCollector struct {
+	namespace string
+	buckets   []float64
+
+	mu         sync.Mutex
+	operations map[metricsKey]*operationMetrics
+}
+
+func newMetricsCollector(cfg *Metrics) *metricsCollector {
+	if cfg == nil {
+		return nil
+	}
+	m := &metricsCollector{
+		namespace:  cfg.Namespace,
+		buckets:    slices.Clone(cfg.Buckets),
+		operations: make(map[metricsKey]*operationMetrics),
+	}
+	if m.namespace == "" {
+		m.namespace = "fiberoapi"
+	}
+	if len(m.buckets) == 0 {
+		m.buckets = slices.Clone(DefaultMetricsBuckets)
+	}
+	slices.Sort(m.buckets)
+	return m
+}
+
+// observe records one request of op. errType is empty for successful
+// requests.
+func (m *metricsCollector) observe(op *OpenAPIOperation, status int, errType string, d time.Duration) {
+	key := metricsKey{operationID: op.Options.OperationID, method: op.Method, route: op.Path}
+	seconds := d.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	om := m.operations[key]
+	if om == nil {
+		om = &operationMetrics{
+			statuses: make(map[int]uint64),
+			errors:   make(map[string]uint64),
+			buckets:  make([]uint64, len(m.buckets)),
+		}
+		m.operations[key] = om
+	}
+	om.statuses[status]++
+	if errType != "" {
+		om.errors[errType]++
+	}
+	if i, _ := slices.BinarySearch(m.buckets, seconds); i < len(m.buckets) {
+		om.buckets[i]++
+	}
+	om.sum += seconds
+	om.count++
+}
+
+// write renders every series in the Prometheus text exposition format,
+// sorted so that successive scrapes are stable.
+func (m *metricsCollector) write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]metricsKey, 0, len(m.operations))
+	for k := range m.operations {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b metricsKey) int {
+		return strings.Compare(a.route+" "+a.method, b.route+" "+b.method)
+	})
+
+	var b strings.Builder
+	requests := m.namespace + "_requests_total"
+	fmt.Fprintf(&b, "# HELP %s Requests served, by operation and status code.\n# TYPE %s counter\n", requests, requests)
+	for _, k := range keys {
+		om := m.operations[k]
+		statuses := make([]int, 0, len(om.statuses))
+		for s := range om.statuses {
+			statuses = append(statuses, s)
+		}
+		slices.Sort(statuses)
+		for _, s := range statuses {
+			fmt.Fprintf(&b, "%s{%s,status=\"%d\"} %d\n", requests, k.labels(), s, om.statuses[s])
+		}
+	}
+
+	duration := m.namespace + "_request_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Request latency in seconds, by operation.\n# TYPE %s histogram\n", duration, duration)
+	for _, k := range keys {
+		om := m.operations[k]
+		var cumulative uint64
+		for i, le := range m.buckets {
+			cumulative += om.buckets[i]
+			fmt.Fprintf(&b, "%s_bucket{%s,le=\"%s\"} %d\n", duration, k.labels(), strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", duration, k.labels(), om.count)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", duration, k.labels(), strconv.FormatFloat(om.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", duration, k.labels(), om.count)
+	}
+
+	errs := m.namespace + "_errors_total"
+	fmt.Fprintf(&b, "# HELP %s Error responses, by operation and error type.\n# TYPE %s counter\n", errs, errs)
+	for _, k := range keys {
+		om := m.operations[k]
+		for _, t := range sortedKeys(om.errors) {
+			fmt.Fprintf(&b, "%s{%s,error_type=\"%s\"} %d\n", errs, k.labels(), escapeLabelValue(t), om.errors[t])
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (k metricsKey) labels() string {
+	return fmt.Sprintf(`operation_id="%s",method="%s",route="%s"`,
+		escapeLabelValue(k.operationID), escapeLabelValue(k.method), escapeLabelValue(k.route))
+}
+
+// escapeLabelValue escapes a label value as the text format requires.
+func escapeLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// MetricsHandler serves the metrics of the app in the Prometheus text
+// format. New mounts it at Metrics.Path; use it directly to serve the metrics
+// elsewhere, e.g. on an internal listener. It answers 404 when Config.Metrics
+// is not set.
+func (o *OApiApp) MetricsHandler() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if o.metrics == nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+		c.Set(fiber.HeaderContentType, metricsContentType)
+		return o.metrics.write(c.Response().BodyWriter())
+	}
+}
+
+// markErrorType records the entry type of the library error answered to the
+// request, which labels it in the metrics.
+func (o *OApiApp) markErrorType(c fiber.Ctx, errType string) {
+	if o.metrics != nil {
+		c.Locals(errorTypeLocalsKey, errType)
+	}
+}
+
+// errorTypeOf returns the error type a response is counted under: the one
+// marked by the library error renderers, else one derived from the status of
+// error responses written by custom handlers and declared error bodies.
+func errorTypeOf(c fiber.Ctx, status int) string {
+	if status < fiber.StatusBadRequest {
+		return ""
+	}
+	if t, ok := c.Locals(errorTypeLocalsKey).(string); ok && t != "" {
+		return t
+	}
+	return statusErrorType(status)
+}
+
+// inputErrorType is the entry type of an error returned by parseInput.
+func inputErrorType(err error) string {
+	if isValidationError(err) {
+		return errTypeValidation
+	}
+	return categorizeError(err, nil).Type
+}
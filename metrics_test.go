@@ -0,0 +1,142 @@
+package fiberoapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMetricsUser(c fiber.Ctx, in uniInput) (uniOutput, error) {
+	if in.Name == "fail" {
+		return uniOutput{}, errors.New("boom")
+	}
+	return uniOutput{Message: "ok"}, nil
+}
+
+func getMetricsOrder(c fiber.Ctx, in struct {
+	ID string `uri:"id"`
+}) (uniOutput, *ErrorResponse) {
+	if in.ID == "taken" {
+		return uniOutput{}, &ErrorResponse{Code: 409, Details: "taken", Type: "conflict"}
+	}
+	return uniOutput{Message: in.ID}, nil
+}
+
+func TestMetrics_CountsByOperationStatusAndErrorType(t *testing.T) {
+	oapi := newTestApp(Config{Metrics: &Metrics{}})
+	Post(oapi, "/users/:name", createMetricsUser, OpenAPIOptions{OperationID: "createUser"})
+	Get(oapi, "/orders/:id", getMetricsOrder, OpenAPIOptions{OperationID: "getOrder"})
+	sendRequest(t, oapi, "POST", "/users/bob", `{}`)
+	sendRequest(t, oapi, "POST", "/users/alice", `{}`)
+	sendRequest(t, oapi, "POST", "/users/a", `{}`)
+	sendRequest(t, oapi, "POST", "/users/bob", `{"age":"x"}`)
+	sendRequest(t, oapi, "POST", "/users/fail", `{}`)
+	sendRequest(t, oapi, "GET", "/orders/taken", "")
+	sendRequest(t, oapi, "GET", "/orders/42", "")
+
+	resp := sendRequest(t, oapi, "GET", "/metrics", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, metricsContentType, resp.Header.Get(fiber.HeaderContentType))
+	body := string(resp.body)
+
+	users := `operation_id="createUser",method="POST",route="/users/:name"`
+	orders := `operation_id="getOrder",method="GET",route="/orders/:id"`
+	for _, line := range []string{
+		"# TYPE fiberoapi_requests_total counter",
+		`fiberoapi_requests_total{` + users + `,status="200"} 2`,
+		`fiberoapi_requests_total{` + users + `,status="400"} 1`,
+		`fiberoapi_requests_total{` + users + `,status="422"} 1`,
+		`fiberoapi_requests_total{` + users + `,status="500"} 1`,
+		`fiberoapi_requests_total{` + orders + `,status="200"} 1`,
+		`fiberoapi_requests_total{` + orders + `,status="409"} 1`,
+		"# TYPE fiberoapi_request_duration_seconds histogram",
+		`fiberoapi_request_duration_seconds_bucket{` + users + `,le="+Inf"} 5`,
+		`fiberoapi_request_duration_seconds_count{` + users + `} 5`,
+		`fiberoapi_request_duration_seconds_count{` + orders + `} 2`,
+		"# TYPE fiberoapi_errors_total counter",
+		`fiberoapi_errors_total{` + users + `,error_type="internal_error"} 1`,
+		`fiberoapi_errors_total{` + users + `,error_type="type_error"} 1`,
+		`fiberoapi_errors_total{` + users + `,error_type="validation_error"} 1`,
+		`fiberoapi_errors_total{` + orders + `,error_type="conflict"} 1`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.NotContains(t, body, "/users/bob", "raw paths must not become labels")
+	assert.NotContains(t, body, `/metrics"`, "the metrics route is not an operation")
+}
+
+func TestMetrics_HistogramBuckets(t *testing.T) {
+	m := newMetricsCollector(&Metrics{Buckets: []float64{0.5, 0.1}})
+	op := &OpenAPIOperation{Method: "GET", Path: "/x", Options: OpenAPIOptions{OperationID: "x"}}
+	m.observe(op, 200, "", 50*time.Millisecond)
+	m.observe(op, 200, "", 200*time.Millisecond)
+	m.observe(op, 200, "", 2*time.Second)
+
+	var b strings.Builder
+	require.NoError(t, m.write(&b))
+	labels := `operation_id="x",method="GET",route="/x"`
+	assert.Contains(t, b.String(), `fiberoapi_request_duration_seconds_bucket{`+labels+`,le="0.1"} 1`+"\n")
+	assert.Contains(t, b.String(), `fiberoapi_request_duration_seconds_bucket{`+labels+`,le="0.5"} 2`+"\n")
+	assert.Contains(t, b.String(), `fiberoapi_request_duration_seconds_bucket{`+labels+`,le="+Inf"} 3`+"\n")
+	assert.Contains(t, b.String(), `fiberoapi_request_duration_seconds_sum{`+labels+`} 2.25`+"\n")
+}
+
+func TestMetrics_AuthAndProblemDetails(t *testing.T) {
+	oapi := newTestApp(Config{
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+		ProblemDetails:  true,
+		Metrics:         &Metrics{},
+	})
+	Get(oapi, "/admin", func(c fiber.Ctx, input struct{}) (fiber.Map, *ErrorResponse) {
+		return fiber.Map{"ok": true}, nil
+	}, WithRoles(OpenAPIOptions{OperationID: "admin"}, "admin"))
+
+	sendRequest(t, oapi, "GET", "/admin", "")
+	sendRequest(t, oapi, "GET", "/admin", "", "Authorization", "Bearer valid-token")
+
+	body := string(sendRequest(t, oapi, "GET", "/metrics", "").body)
+	labels := `operation_id="admin",method="GET",route="/admin"`
+	assert.Contains(t, body, `fiberoapi_errors_total{`+labels+`,error_type="authentication_error"} 1`)
+	assert.Contains(t, body, `fiberoapi_errors_total{`+labels+`,error_type="authorization_error"} 1`)
+}
+
+func TestMetrics_PathNamespaceAndHandler(t *testing.T) {
+	oapi := newTestApp(Config{Metrics: &Metrics{Path: "/internal/metrics", Namespace: "shop"}})
+	Get(oapi, "/orders/:id", getMetricsOrder, OpenAPIOptions{OperationID: "getOrder"})
+	sendRequest(t, oapi, "GET", "/orders/1", "")
+	resp := sendRequest(t, oapi, "GET", "/internal/metrics", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, string(resp.body), `shop_requests_total{operation_id="getOrder",method="GET",route="/orders/:id",status="200"} 1`)
+
+	oapi = newTestApp(Config{Metrics: &Metrics{Path: "-"}})
+	Get(oapi, "/orders/:id", getMetricsOrder, OpenAPIOptions{OperationID: "getOrder"})
+	resp = sendRequest(t, oapi, "GET", "/metrics", "")
+	assert.Equal(t, 404, resp.StatusCode)
+	admin := newTestApp()
+	admin.FiberApp().Get("/metrics", oapi.MetricsHandler())
+	sendRequest(t, oapi, "GET", "/orders/1", "")
+	resp = sendRequest(t, admin, "GET", "/metrics", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, string(resp.body), `route="/orders/:id",status="200"} 1`)
+}
+
+func TestMetrics_Disabled(t *testing.T) {
+	oapi := newTestApp()
+	Get(oapi, "/orders/:id", getMetricsOrder, OpenAPIOptions{})
+	assert.Nil(t, oapi.metrics)
+	assert.Equal(t, 404, sendRequest(t, oapi, "GET", "/metrics", "").StatusCode)
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabelValue("a\"b\\c\nd"))
+}
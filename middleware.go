@@ -0,0 +1,59 @@
+package fiberoapi
+
+import "github.com/gofiber/fiber/v3"
+
+// operationLocalsKey is the fiber.Ctx locals key holding the *OpenAPIOperation
+// serving the current request.
+const operationLocalsKey = "fiberoapi.operation"
+
+// OperationFromCtx returns the operation serving the request: its method,
+// path, operationId, tags, required roles and the rest of its options. It is
+// available to OpenAPIOptions.Middleware and to the handler itself, which lets
+// shared middleware make per-operation decisions:
+//
+//	audit := func(c fiber.Ctx) error {
+//		if op, ok := fiberoapi.OperationFromCtx(c); ok && slices.Contains(op.Options.Tags, "admin") {
+//			log.Printf("admin call: %s", op.Options.OperationID)
+//		}
+//		return c.Next()
+//	}
+//
+// The returned operation is shared across requests and must not be modified.
+func OperationFromCtx(c fiber.Ctx) (*OpenAPIOperation, bool) {
+	op, ok := c.Locals(operationLocalsKey).(*OpenAPIOperation)
+	return op, ok
+}
+
+// operationHandlers builds the fiber handler chain of an operation: a binder
+// selecting the API version (see Versioning), exposing op through
+// OperationFromCtx and reporting the request to Config.Hooks and
+// Config.Metrics, then op's middleware in declaration order, then the
+// operation handler.
+func (o *OApiApp) operationHandlers(op *OpenAPIOperation, handler fiber.Handler) []any {
+	chain := make([]any, 0, len(op.Options.Middleware)+2)
+	chain = append(chain, func(c fiber.Ctx) error {
+		if op.Version != "" {
+			if handled, err := o.selectVersion(c, op); handled {
+				return err
+			}
+		}
+		c.Locals(operationLocalsKey, op)
+		if o.config.Hooks != nil || o.metrics != nil {
+			return o.observe(c, op, c.Next)
+		}
+		return c.Next()
+	})
+	if op.Version != "" && o.config.Versioning.Strategy != VersionByPath {
+		// Every version of the operation is a separate route on the same
+		// path. A route serving another version than the requested one must
+		// hand over to the next route without running its own handlers.
+		for _, mw := range op.Options.Middleware {
+			chain = append(chain, skipOtherVersion(op, mw))
+		}
+		return append(chain, skipOtherVersion(op, handler))
+	}
+	for _, mw := range op.Options.Middleware {
+		chain = append(chain, mw)
+	}
+	return append(chain, handler)
+}
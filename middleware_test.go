@@ -0,0 +1,120 @@
+package fiberoapi
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mwInput struct {
+	ID string `uri:"id" validate:"required,min=2"`
+}
+
+type mwOutput struct {
+	OperationID string `json:"operationId"`
+}
+
+func TestMiddleware_RunsInOrderBeforeParsing(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app)
+
+	var trace []string
+	record := func(name string) fiber.Handler {
+		return func(c fiber.Ctx) error {
+			trace = append(trace, name)
+			return c.Next()
+		}
+	}
+	Get(oapi, "/items/:id", func(c fiber.Ctx, in mwInput) (mwOutput, error) {
+		trace = append(trace, "handler")
+		return mwOutput{}, nil
+	}, OpenAPIOptions{Middleware: []fiber.Handler{record("first"), record("second")}})
+	Get(oapi, "/other/:id", func(c fiber.Ctx, in mwInput) (mwOutput, error) {
+		return mwOutput{}, nil
+	}, OpenAPIOptions{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/items/42", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, []string{"first", "second", "handler"}, trace)
+
+	trace = nil
+	resp, err = app.Test(httptest.NewRequest("GET", "/items/1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 422, resp.StatusCode)
+	assert.Equal(t, []string{"first", "second"}, trace, "middleware runs even when the input is invalid")
+
+	trace = nil
+	_, err = app.Test(httptest.NewRequest("GET", "/other/42", nil))
+	require.NoError(t, err)
+	assert.Empty(t, trace, "middleware is scoped to its operation")
+}
+
+func TestMiddleware_ShortCircuit(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app)
+	Get(oapi, "/items/:id", func(c fiber.Ctx, in mwInput) (mwOutput, error) {
+		t.Fatal("handler must not run")
+		return mwOutput{}, nil
+	}, OpenAPIOptions{Middleware: []fiber.Handler{func(c fiber.Ctx) error {
+		return c.Status(429).SendString("slow down")
+	}}})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/items/42", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "slow down", string(body))
+}
+
+func TestMiddleware_OperationFromCtx(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app)
+	v1 := Group(oapi, "/v1")
+
+	var seen *OpenAPIOperation
+	inspect := func(c fiber.Ctx) error {
+		op, ok := OperationFromCtx(c)
+		require.True(t, ok)
+		seen = op
+		return c.Next()
+	}
+	Get(v1, "/items/:id", func(c fiber.Ctx, in mwInput) (mwOutput, error) {
+		op, _ := OperationFromCtx(c)
+		return mwOutput{OperationID: op.Options.OperationID}, nil
+	}, OpenAPIOptions{
+		OperationID:   "getItem",
+		Tags:          []string{"items"},
+		RequiredRoles: []string{"reader"},
+		Security:      "disabled",
+		Middleware:    []fiber.Handler{inspect},
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/v1/items/42", nil))
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.True(t, strings.Contains(string(body), `"operationId":"getItem"`), "%s", body)
+
+	require.NotNil(t, seen)
+	assert.Equal(t, "GET", seen.Method)
+	assert.Equal(t, "/v1/items/:id", seen.Path)
+	assert.Equal(t, []string{"items"}, seen.Options.Tags)
+	assert.Equal(t, []string{"reader"}, seen.Options.RequiredRoles)
+}
+
+func TestMiddleware_OperationFromCtxOutsideOperation(t *testing.T) {
+	app := fiber.New()
+	var ok bool
+	app.Get("/plain", func(c fiber.Ctx) error {
+		_, ok = OperationFromCtx(c)
+		return nil
+	})
+	_, err := app.Test(httptest.NewRequest("GET", "/plain", nil))
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
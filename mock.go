@@ -0,0 +1,183 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// mockErrorHeader selects which declared error a mock operation returns. Its
+// value is either the status code ("404") or the error type name
+// ("NotFoundError", case-insensitive).
+const mockErrorHeader = "X-Mock-Error"
+
+// maxMockDepth bounds synthesis of recursive types; deeper values are null.
+const maxMockDepth = 8
+
+// NewMockApp loads the routes registered by register (see LoadRoutes) on an
+// app running in mock mode: no handler is ever called. Each request goes
+// through the real parsing, validation and authorization pipeline, so
+// malformed requests get the same 400 / 422 / 401 responses as in
+// production, then receives:
+//   - the declared error whose status or type name matches the X-Mock-Error
+//     request header, when the header is present;
+//   - otherwise OpenAPIOptions.Example when set;
+//   - otherwise a payload synthesised from the output schema (enums, formats,
+//     min / max and length constraints are respected).
+//
+// The docs and spec routes are served as usual:
+//
+//	mock, _ := fiberoapi.NewMockApp(api.RegisterRoutes)
+//	mock.Listen(":3000")
+func NewMockApp(register any, config ...Config) (*OApiApp, error) {
+	oapi, err := LoadRoutes(register, config...)
+	if err != nil {
+		return nil, err
+	}
+	oapi.config.Mock = true
+	return oapi, nil
+}
+
+// serveMock writes the mock response for an operation whose input has been
+// parsed and validated.
+func (o *OApiApp) serveMock(c fiber.Ctx, checker *responseChecker) error {
+	op := checker.op
+
+	if selector := c.Get(mockErrorHeader); selector != "" {
+		for _, errInst := range op.Options.Errors {
+			if errInst == nil {
+				continue
+			}
+			status := extractErrorStatusCode(errInst)
+			name := dereferenceType(reflect.TypeOf(errInst)).Name()
+			if selector == strconv.Itoa(status) || strings.EqualFold(selector, name) {
+				return c.Status(status).JSON(errInst)
+			}
+		}
+		msg := fmt.Sprintf("no declared error matches %q; declared: %s", selector, describeDeclaredErrors(op.Options.Errors))
+		return o.sendError(c, statusParseError, errorCategory{
+			Code:    statusParseError,
+			Type:    errTypeParse,
+			Message: msg,
+		}, []ValidationErrorEntry{{
+			Type:  errTypeParse,
+			Code:  statusParseError,
+			Loc:   []any{"header", mockErrorHeader},
+			Field: mockErrorHeader,
+			Msg:   msg,
+		}})
+	}
+
+	if op.Options.Example != nil {
+		return c.JSON(op.Options.Example)
+	}
+	checker.once.Do(checker.init)
+	return c.JSON(synthesizeValue(checker.schema, checker.components, 0))
+}
+
+func describeDeclaredErrors(errs []any) string {
+	var parts []string
+	for _, errInst := range errs {
+		if errInst == nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d (%s)", extractErrorStatusCode(errInst), dereferenceType(reflect.TypeOf(errInst)).Name()))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// synthesizeValue builds a JSON-ready value that satisfies schema, as
+// produced by generateSchema. Explicit examples and enums win; strings honour
+// format and length bounds, numbers honour minimum / maximum, arrays get a
+// single item.
+func synthesizeValue(schema map[string]interface{}, components map[string]interface{}, depth int) any {
+	if schema == nil || depth > maxMockDepth {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		target, _ := components[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{})
+		return synthesizeValue(target, components, depth+1)
+	}
+	if example, ok := schema["example"]; ok && schema["type"] != "object" {
+		return example
+	}
+	if enum, ok := schema["enum"].([]string); ok && len(enum) > 0 {
+		// oneof values are always strings in the schema; keep numeric fields numeric.
+		if schema["type"] == "integer" || schema["type"] == "number" {
+			if f, err := strconv.ParseFloat(enum[0], 64); err == nil {
+				if schema["type"] == "integer" {
+					return int(f)
+				}
+				return f
+			}
+		}
+		return enum[0]
+	}
+
+	switch schema["type"] {
+	case "string":
+		return mockString(schema)
+	case "integer":
+		return int(mockNumber(schema, 1))
+	case "number":
+		return mockNumber(schema, 1.5)
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		return []any{synthesizeValue(items, components, depth+1)}
+	case "object":
+		out := map[string]any{}
+		props, _ := schema["properties"].(map[string]interface{})
+		for name, sub := range props {
+			subSchema, _ := sub.(map[string]interface{})
+			out[name] = synthesizeValue(subSchema, components, depth+1)
+		}
+		if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok && len(props) == 0 {
+			out["key"] = synthesizeValue(extra, components, depth+1)
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+func mockString(schema map[string]interface{}) string {
+	var s string
+	switch schema["format"] {
+	case "email":
+		s = "user@example.com"
+	case "uuid":
+		s = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri":
+		s = "https://example.com"
+	case "date-time":
+		s = "2006-01-02T15:04:05Z"
+	default:
+		s = "string"
+	}
+	if min, ok := schema["minLength"].(int); ok && len(s) < min {
+		s += strings.Repeat("x", min-len(s))
+	}
+	if max, ok := schema["maxLength"].(int); ok && max >= 0 && len(s) > max {
+		s = s[:max]
+	}
+	return s
+}
+
+func mockNumber(schema map[string]interface{}, preferred float64) float64 {
+	v := preferred
+	if min, ok := schema["minimum"].(int); ok && v < float64(min) {
+		v = float64(min)
+	}
+	if max, ok := schema["maximum"].(int); ok && v > float64(max) {
+		v = float64(max)
+	}
+	return v
+}
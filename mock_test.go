@@ -0,0 +1,131 @@
+package fiberoapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAddress struct {
+	City string `json:"city" validate:"required,min=12"`
+}
+
+type mockAccount struct {
+	ID       string            `json:"id" validate:"uuid4"`
+	Email    string            `json:"email" validate:"required,email"`
+	Plan     string            `json:"plan" validate:"oneof=pro free"`
+	Level    int               `json:"level" validate:"oneof=3 5"`
+	Seats    int               `json:"seats" validate:"min=5,max=10"`
+	Code     string            `json:"code" validate:"max=3"`
+	Ratio    float64           `json:"ratio" validate:"max=1"`
+	Address  mockAddress       `json:"address"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Verified bool              `json:"verified"`
+}
+
+type mockAccountInput struct {
+	ID string `uri:"id" validate:"required,min=2"`
+}
+
+type mockNotFound struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mockConflict struct{ Reason string }
+
+func (mockConflict) HTTPStatus() int { return 409 }
+
+func TestMock_SynthesisedPayloadMatchesSchema(t *testing.T) {
+	// Response validation in reject mode turns any schema mismatch into a
+	// 500, so a 200 proves the synthetic payload conforms.
+	app, err := NewMockApp(func(router OApiRouter) {
+		Get(router, "/accounts/:id", func(c fiber.Ctx, in mockAccountInput) (mockAccount, error) {
+			panic("handlers must not run in mock mode")
+		}, OpenAPIOptions{})
+	}, Config{ResponseValidation: ResponseValidationReject})
+	require.NoError(t, err)
+	app.config.ResponseViolationHandler = func(c fiber.Ctx, v ResponseViolation) { t.Errorf("violation: %+v", v.Errors) }
+
+	resp := sendRequest(t, app, "GET", "/accounts/42", "")
+	require.Equal(t, 200, resp.StatusCode, "%s", resp.body)
+
+	var got mockAccount
+	resp.decode(t, &got)
+	assert.Equal(t, "3fa85f64-5717-4562-b3fc-2c963f66afa6", got.ID)
+	assert.Equal(t, "user@example.com", got.Email)
+	assert.Equal(t, "pro", got.Plan)
+	assert.Equal(t, 3, got.Level)
+	assert.Equal(t, 5, got.Seats)
+	assert.Equal(t, "str", got.Code)
+	assert.Equal(t, 1.0, got.Ratio)
+	assert.Len(t, got.Address.City, 12)
+	assert.Equal(t, []string{"string"}, got.Tags)
+	assert.Equal(t, map[string]string{"key": "string"}, got.Labels)
+	assert.True(t, got.Verified)
+}
+
+func TestMock_RequestsAreStillValidated(t *testing.T) {
+	app, err := NewMockApp(func(router OApiRouter) {
+		Get(router, "/accounts/:id", func(c fiber.Ctx, in mockAccountInput) (mockAccount, error) {
+			panic("handlers must not run in mock mode")
+		}, OpenAPIOptions{})
+	})
+	require.NoError(t, err)
+
+	resp := sendRequest(t, app, "GET", "/accounts/1", "")
+	assert.Equal(t, 422, resp.StatusCode)
+	assert.Contains(t, string(resp.body), `"loc":["path","id"]`)
+}
+
+func TestMock_ExampleServedAndDocumented(t *testing.T) {
+	app, err := NewMockApp(func(router OApiRouter) {
+		Get(router, "/accounts/:id/example", func(c fiber.Ctx, in mockAccountInput) (mockAccount, error) {
+			panic("handlers must not run in mock mode")
+		}, OpenAPIOptions{Example: mockAccount{ID: "fixed", Plan: "pro"}})
+	})
+	require.NoError(t, err)
+
+	resp := sendRequest(t, app, "GET", "/accounts/42/example", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, string(resp.body), `"id":"fixed"`)
+
+	spec := app.GenerateOpenAPISpec()
+	op := spec["paths"].(map[string]interface{})["/accounts/{id}/example"].(map[string]interface{})["get"].(map[string]interface{})
+	media := op["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	assert.Equal(t, mockAccount{ID: "fixed", Plan: "pro"}, media["example"])
+}
+
+func TestMock_ErrorSelection(t *testing.T) {
+	app, err := NewMockApp(func(router OApiRouter) {
+		Get(router, "/accounts/:id", func(c fiber.Ctx, in mockAccountInput) (mockAccount, error) {
+			panic("handlers must not run in mock mode")
+		}, OpenAPIOptions{Errors: []any{&mockNotFound{Code: 404, Message: "account not found"}, mockConflict{Reason: "locked"}}})
+	})
+	require.NoError(t, err)
+
+	resp := sendRequest(t, app, "GET", "/accounts/42", "", "X-Mock-Error", "404")
+	assert.Equal(t, 404, resp.StatusCode)
+	assert.JSONEq(t, `{"code":404,"message":"account not found"}`, string(resp.body))
+
+	resp = sendRequest(t, app, "GET", "/accounts/42", "", "X-Mock-Error", "mockconflict")
+	assert.Equal(t, 409, resp.StatusCode, "selection by type name uses HTTPStatus()")
+	assert.JSONEq(t, `{"Reason":"locked"}`, string(resp.body))
+
+	resp = sendRequest(t, app, "GET", "/accounts/42", "", "X-Mock-Error", "500")
+	assert.Equal(t, 400, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, []any{"header", "X-Mock-Error"}, env.Errors[0].Loc)
+	assert.True(t, strings.HasSuffix(env.Errors[0].Msg, "declared: 404 (mockNotFound), 409 (mockConflict)"), env.Errors[0].Msg)
+}
+
+func TestMock_ConfigFlag(t *testing.T) {
+	oapi := newTestApp(Config{Mock: true})
+	assert.True(t, oapi.Config().Mock)
+	assert.True(t, oapi.Config().EnableValidation, "Mock alone keeps the defaults")
+}
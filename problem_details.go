@@ -0,0 +1,121 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// problemContentType is the media type of RFC 9457 documents.
+const problemContentType = "application/problem+json"
+
+// ProblemDetails is the RFC 9457 document emitted for every library error when
+// Config.ProblemDetails is set. Errors is an extension member carrying the
+// same per-field entries (loc / constraint / field) as ErrorEnvelope, so no
+// information is lost when switching formats.
+type ProblemDetails struct {
+	Type      string                 `json:"type"`                 // URI identifying the problem type; "about:blank" unless Config.ProblemTypeBaseURI is set
+	Title     string                 `json:"title"`                // Short summary of the problem type
+	Status    int                    `json:"status"`               // HTTP status code
+	Detail    string                 `json:"detail,omitempty"`     // Explanation specific to this occurrence
+	Instance  string                 `json:"instance,omitempty"`   // Request path of this occurrence
+	Errors    []ValidationErrorEntry `json:"errors"`               // Per-field entries, as in ErrorEnvelope
+	RequestID string                 `json:"request_id,omitempty"` // ResponseContext.ResponseID
+	TraceID   string                 `json:"trace_id,omitempty"`   // ResponseContext.TraceID
+	SpanID    string                 `json:"span_id,omitempty"`    // ResponseContext.SpanID
+}
+
+// newProblem builds the document for a status and its entries. The problem
+// type is taken from the first entry: every entry of one response shares the
+// status, and in practice the category.
+func (o *OApiApp) newProblem(status int, entries []ValidationErrorEntry, instance string, rc ResponseContext) ProblemDetails {
+	errType := statusErrorType(status)
+	if len(entries) > 0 && entries[0].Type != "" {
+		errType = entries[0].Type
+	}
+	p := ProblemDetails{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Instance:  instance,
+		Errors:    entries,
+		RequestID: rc.ResponseID,
+		TraceID:   rc.TraceID,
+		SpanID:    rc.SpanID,
+	}
+	if base := o.config.ProblemTypeBaseURI; base != "" {
+		p.Type = base + errType
+		p.Title = problemTitle(errType)
+	}
+	if p.Errors == nil {
+		p.Errors = []ValidationErrorEntry{}
+	}
+	switch len(entries) {
+	case 0:
+	case 1:
+		p.Detail = entries[0].Msg
+	default:
+		p.Detail = fmt.Sprintf("%d errors occurred; see errors for details", len(entries))
+	}
+	return p
+}
+
+// problemTitle turns an entry type such as "validation_error" into
+// "Validation error".
+func problemTitle(errType string) string {
+	title := strings.ReplaceAll(errType, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
+}
+
+// sendProblem writes entries as an application/problem+json response.
+func (o *OApiApp) sendProblem(c fiber.Ctx, status int, entries []ValidationErrorEntry) error {
+	p := o.newProblem(status, entries, sanitizePath(c.Path()), responseContext(c))
+	return c.Status(status).JSON(p, problemContentType)
+}
+
+// sendError writes a library error in the configured format: a ProblemDetails
+// document, the DefaultErrorShape filled from cat, or an ErrorEnvelope holding
+// entries.
+func (o *OApiApp) sendError(c fiber.Ctx, status int, cat errorCategory, entries []ValidationErrorEntry) error {
+	o.markErrorType(c, cat.Type)
+	if o.config.ProblemDetails {
+		return o.sendProblem(c, status, entries)
+	}
+	if o.config.DefaultErrorShape != nil {
+		return c.Status(status).JSON(materializeError(o.config.DefaultErrorShape, cat))
+	}
+	return c.Status(status).JSON(ErrorEnvelope{
+		Errors:          entries,
+		ResponseContext: responseContext(c),
+	})
+}
+
+// libraryErrorShape is the value whose type every library error is decoded
+// as by the generated clients: ProblemDetails, the DefaultErrorShape, or nil
+// for the ErrorEnvelope.
+func (o *OApiApp) libraryErrorShape() any {
+	if o.config.ProblemDetails {
+		return ProblemDetails{}
+	}
+	return o.config.DefaultErrorShape
+}
+
+// problemSpecContent documents an error response as application/problem+json,
+// with an example built from the envelope example the default mode shows.
+func (o *OApiApp) problemSpecContent(instance string, env ErrorEnvelope) map[string]interface{} {
+	status := statusValidationError
+	if len(env.Errors) > 0 {
+		status = env.Errors[0].Code
+	}
+	return map[string]interface{}{
+		problemContentType: map[string]interface{}{
+			"schema":  map[string]interface{}{"$ref": "#/components/schemas/ProblemDetails"},
+			"example": o.newProblem(status, env.Errors, instance, env.ResponseContext),
+		},
+	}
+}
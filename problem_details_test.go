@@ -0,0 +1,140 @@
+package fiberoapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errProblemMissing = errors.New("user is archived")
+
+func createProblemUser(c fiber.Ctx, in uniInput) (uniOutput, error) {
+	if in.Name == "archived" {
+		return uniOutput{}, errProblemMissing
+	}
+	return uniOutput{Message: "ok"}, nil
+}
+
+func TestProblemDetails_Validation(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	Post(oapi, "/users/:name", createProblemUser, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{"age":3}`, "X-Request-Id", "req-1")
+	p := resp.problem(t)
+
+	require.Equal(t, 422, resp.StatusCode)
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, "Unprocessable Entity", p.Title)
+	assert.Equal(t, 422, p.Status)
+	assert.Equal(t, "/users/a", p.Instance)
+	assert.Equal(t, "req-1", p.RequestID)
+	require.Len(t, p.Errors, 2)
+	assert.Equal(t, "2 errors occurred; see errors for details", p.Detail)
+	assert.Equal(t, "validation_error", p.Errors[0].Type)
+	assert.NotEmpty(t, p.Errors[0].Loc)
+	assert.NotEmpty(t, p.Errors[0].Constraint)
+}
+
+func TestProblemDetails_Categories(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true, ProblemTypeBaseURI: "https://errors.example.com/"})
+	oapi.MapError(errProblemMissing, 404, "not_found")
+	Post(oapi, "/users/:name", createProblemUser, OpenAPIOptions{})
+	oapi.UseNotFoundHandler()
+
+	cases := []struct {
+		name, method, path, body string
+		status                   int
+		typ, title               string
+	}{
+		{"parse", "POST", "/users/bob", `{"age":"x"}`, 400, "type_error", "Type error"},
+		{"mapped handler error", "POST", "/users/archived", `{}`, 404, "not_found", "Not found"},
+		{"no route", "GET", "/nowhere", ``, 404, "not_found", "Not found"},
+		{"wrong method", "GET", "/users/bob", ``, 405, "method_not_allowed", "Method not allowed"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sendRequest(t, oapi, tc.method, tc.path, tc.body)
+			p := resp.problem(t)
+			require.Equal(t, tc.status, resp.StatusCode)
+			assert.Equal(t, tc.status, p.Status)
+			assert.Equal(t, "https://errors.example.com/"+tc.typ, p.Type)
+			assert.Equal(t, tc.title, p.Title)
+			require.Len(t, p.Errors, 1)
+			assert.Equal(t, tc.typ, p.Errors[0].Type)
+			assert.Equal(t, p.Errors[0].Msg, p.Detail)
+		})
+	}
+}
+
+func TestProblemDetails_OverridesDefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true, DefaultErrorShape: &UniErr{}})
+	oapi.MapError(errProblemMissing, 404, "not_found")
+	Post(oapi, "/users/:name", createProblemUser, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/archived", `{}`)
+	p := resp.problem(t)
+	require.Equal(t, 404, resp.StatusCode)
+	assert.Equal(t, "user is archived", p.Detail)
+	assert.Equal(t, "about:blank", p.Type)
+}
+
+func TestProblemDetails_Spec(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	Post(oapi, "/users/:name", createProblemUser, OpenAPIOptions{})
+	oapi.UseNotFoundHandler()
+	spec := oapi.GenerateOpenAPISpec()
+
+	responses := spec["paths"].(map[string]interface{})["/users/{name}"].(map[string]interface{})["post"].(map[string]interface{})["responses"].(map[string]interface{})
+	for _, code := range []string{"400", "404", "422"} {
+		content := responses[code].(map[string]interface{})["content"].(map[string]interface{})
+		assert.Equal(t, []string{problemContentType}, sortedKeys(content), code)
+		media := content[problemContentType].(map[string]interface{})
+		assert.Equal(t, "#/components/schemas/ProblemDetails", media["schema"].(map[string]interface{})["$ref"])
+		example := media["example"].(ProblemDetails)
+		assert.Equal(t, code, statusCodeKey(example.Status))
+		assert.NotEmpty(t, example.Errors)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	require.Contains(t, schemas, "ProblemDetails")
+	props := schemas["ProblemDetails"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, name := range []string{"type", "title", "status", "detail", "instance", "errors"} {
+		assert.Contains(t, props, name)
+	}
+}
+
+func TestProblemDetails_SpecDefaults(t *testing.T) {
+	_, oapi := registerUniRoute(t)
+	schemas := oapi.GenerateOpenAPISpec()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.NotContains(t, schemas, "ProblemDetails")
+}
+
+func TestProblemDetails_Generators(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	Post(oapi, "/users/:name", createProblemUser, OpenAPIOptions{OperationID: "createUser"})
+
+	goSrc, err := oapi.GenerateGoClient(GoClientConfig{PackageName: "client"})
+	require.NoError(t, err)
+	assert.Contains(t, string(goSrc), "decodeAs[ProblemDetails]")
+	assert.NotContains(t, string(goSrc), "decodeAs[ErrorEnvelope]")
+
+	tsSrc, err := oapi.GenerateTypeScript(TypeScriptConfig{})
+	require.NoError(t, err)
+	assert.Contains(t, string(tsSrc), "export type CreateUserError = ProblemDetails;")
+}
+
+func TestProblemDetails_DeclaredErrorBodiesKeepTheirShape(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	Get(oapi, "/locks", func(c fiber.Ctx, in struct{}) (uniOutput, *clientGenConflict) {
+		return uniOutput{}, &clientGenConflict{Message: "locked"}
+	}, OpenAPIOptions{OperationID: "lock", Errors: []any{clientGenConflict{}}})
+
+	resp := sendRequest(t, oapi, "GET", "/locks", "")
+	assert.Equal(t, 409, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+	responses := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/locks"].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})
+	content := responses["409"].(map[string]interface{})["content"].(map[string]interface{})
+	assert.Equal(t, []string{"application/json"}, sortedKeys(content))
+}
@@ -0,0 +1,238 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// errTypeRateLimited is the entry type of the 429 answered when an operation's
+// OpenAPIOptions.RateLimit is exhausted.
+const errTypeRateLimited = "rate_limit_exceeded"
+
+// RateLimitKey selects what a rate limit counts requests by.
+type RateLimitKey string
+
+const (
+	RateLimitByIP     RateLimitKey = "ip"      // Client IP (c.IP(), honouring Fiber's proxy settings)
+	RateLimitByUser   RateLimitKey = "user"    // AuthContext.UserID; the IP until the caller is authenticated
+	RateLimitByAPIKey RateLimitKey = "api_key" // Value of RateLimit.Header; the IP when it is absent
+)
+
+// RateLimit declares how many requests an operation accepts per window from
+// one client. Requests refill continuously (token bucket), so a client may
+// burst up to Requests and then gets one more every Window/Requests.
+type RateLimit struct {
+	Requests int           // Requests allowed per window
+	Window   time.Duration // Window length
+	Key      RateLimitKey  // What requests are counted by (default: RateLimitByIP)
+	Header   string        // API key header for RateLimitByAPIKey (default: "X-API-Key")
+}
+
+// RateLimitResult is the decision of a RateLimitStore for one request.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // Requests left in the bucket after this one
+	Reset      time.Duration // Until the bucket is full again
+	RetryAfter time.Duration // Until the next request is allowed, when denied
+}
+
+// RateLimitStore holds the token buckets of rate-limited operations. Take
+// consumes one token from the bucket of key, whose capacity is limit and
+// which refills completely in window. Implement it over a shared store
+// (Redis...) to enforce limits across instances.
+type RateLimitStore interface {
+	Take(key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// MemoryRateLimitStore is the default in-process RateLimitStore. Idle buckets
+// are dropped once they have refilled.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	window time.Duration
+}
+
+// NewMemoryRateLimitStore returns an empty in-memory store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*tokenBucket), now: time.Now}
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(key string, limit int, window time.Duration) (RateLimitResult, error) {
+	if limit <= 0 || window <= 0 {
+		return RateLimitResult{}, fmt.Errorf("invalid rate limit %d per %s", limit, window)
+	}
+	now := s.now()
+	capacity := float64(limit)
+	perToken := window.Seconds() / capacity
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b := s.buckets[key]
+	if b == nil {
+		b = &tokenBucket{tokens: capacity, last: now, window: window}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()/perToken)
+	b.last = now
+
+	res := RateLimitResult{Allowed: b.tokens >= 1}
+	if res.Allowed {
+		b.tokens--
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) * perToken)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((capacity - b.tokens) * perToken)
+	return res, nil
+}
+
+// sweep drops the buckets left idle long enough to be full, at most once a
+// minute.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if now.Sub(b.last) >= b.window {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// rateLimitClient returns what a request is counted by under limit.
+func rateLimitClient(c fiber.Ctx, limit *RateLimit) string {
+	switch limit.Key {
+	case RateLimitByUser:
+		if auth, ok := c.Locals("auth").(*AuthContext); ok && auth != nil && auth.UserID != "" {
+			return "user:" + auth.UserID
+		}
+	case RateLimitByAPIKey:
+		if key := c.Get(limit.apiKeyHeader()); key != "" {
+			return "api_key:" + key
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+func (l *RateLimit) apiKeyHeader() string {
+	if l.Header == "" {
+		return "X-API-Key"
+	}
+	return l.Header
+}
+
+// rateLimitedAfterAuth reports whether limit needs the AuthContext and is
+// therefore enforced after authorization, or on rejection, instead of before
+// parsing.
+func (l *RateLimit) rateLimitedAfterAuth() bool {
+	return l.Key == RateLimitByUser
+}
+
+// enforceRateLimit takes a token for the request and sets the RateLimit-*
+// headers. It answers 429 and reports handled when the limit is exhausted.
+// A failing store lets the request through.
+func (o *OApiApp) enforceRateLimit(c fiber.Ctx, op *OpenAPIOperation) (handled bool, err error) {
+	limit := op.Options.RateLimit
+	key := op.Method + " " + op.Path + " " + op.Version + " " + rateLimitClient(c, limit)
+	res, err := o.rateLimits.Take(key, limit.Requests, limit.Window)
+	if err != nil {
+		slog.Warn("fiberoapi: rate limit store failed, request allowed",
+			"method", op.Method, "path", op.Path, "error", err.Error())
+		return false, nil
+	}
+
+	c.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Window)))
+	c.Set("RateLimit-Limit", strconv.Itoa(limit.Requests))
+	c.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	c.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+	if res.Allowed {
+		return false, nil
+	}
+	retry := max(ceilSeconds(res.RetryAfter), 1)
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retry))
+	return true, o.writeErrorCategory(c, rateLimitCategory(limit, retry))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func rateLimitCategory(limit *RateLimit, retryAfter int) errorCategory {
+	return errorCategory{
+		Code:    fiber.StatusTooManyRequests,
+		Type:    errTypeRateLimited,
+		Message: fmt.Sprintf("rate limit of %d requests per %s exceeded; retry in %ds", limit.Requests, limit.Window, retryAfter),
+		Details: fmt.Sprintf("%d;w=%d", limit.Requests, ceilSeconds(limit.Window)),
+	}
+}
+
+// addRateLimitToSpec publishes the rate limit of an operation as x-rate-limit.
+func addRateLimitToSpec(operation map[string]interface{}, opts OpenAPIOptions) {
+	limit := opts.RateLimit
+	if limit == nil {
+		return
+	}
+	key := limit.Key
+	if key == "" {
+		key = RateLimitByIP
+	}
+	ext := map[string]interface{}{
+		"requests": limit.Requests,
+		"window":   ceilSeconds(limit.Window),
+		"key":      string(key),
+	}
+	if key == RateLimitByAPIKey {
+		ext["header"] = limit.apiKeyHeader()
+	}
+	operation["x-rate-limit"] = ext
+}
+
+// rateLimitSpecHeaders documents the headers of a 429 response.
+func rateLimitSpecHeaders() map[string]interface{} {
+	integer := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"schema":      map[string]interface{}{"type": "integer"},
+		}
+	}
+	return map[string]interface{}{
+		"Retry-After":         integer("Seconds until the next request is allowed"),
+		"RateLimit-Limit":     integer("Requests allowed per window"),
+		"RateLimit-Remaining": integer("Requests left in the current window"),
+		"RateLimit-Reset":     integer("Seconds until the limit is fully restored"),
+	}
+}
+
+func exampleRateLimitEnvelope(limit *RateLimit) func() ErrorEnvelope {
+	return func() ErrorEnvelope {
+		cat := rateLimitCategory(limit, 1)
+		return exampleEnvelope(ValidationErrorEntry{
+			Type:       cat.Type,
+			Code:       cat.Code,
+			Loc:        []any{},
+			Msg:        cat.Message,
+			Constraint: cat.Details,
+		})
+	}
+}
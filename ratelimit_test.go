@@ -0,0 +1,244 @@
+package fiberoapi
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func newTestStore() (*MemoryRateLimitStore, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryRateLimitStore()
+	store.now = clock.now
+	return store, clock
+}
+
+func TestMemoryRateLimitStore_TokenBucket(t *testing.T) {
+	store, clock := newTestStore()
+
+	res, err := store.Take("k", 2, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitResult{Allowed: true, Remaining: 1, Reset: 500 * time.Millisecond}, res)
+
+	res, _ = store.Take("k", 2, time.Second)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, time.Second, res.Reset)
+
+	res, _ = store.Take("k", 2, time.Second)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	// Other keys have their own bucket.
+	res, _ = store.Take("other", 2, time.Second)
+	assert.True(t, res.Allowed)
+
+	clock.t = clock.t.Add(500 * time.Millisecond)
+	res, _ = store.Take("k", 2, time.Second)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	_, err = store.Take("k", 0, time.Second)
+	assert.Error(t, err)
+}
+
+func TestMemoryRateLimitStore_SweepsIdleBuckets(t *testing.T) {
+	store, clock := newTestStore()
+	store.Take("idle", 5, time.Second)
+	clock.t = clock.t.Add(2 * time.Minute)
+	store.Take("busy", 5, 10*time.Minute)
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
+
+// failingStore is a RateLimitStore whose backend is down.
+type failingStore struct{}
+
+func (failingStore) Take(string, int, time.Duration) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("connection refused")
+}
+
+func getReport(c fiber.Ctx, in struct{}) (uniOutput, error) {
+	return uniOutput{Message: "ok"}, nil
+}
+
+func TestRateLimit_EnforcedWithHeaders(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{RateLimit: &RateLimit{Requests: 2, Window: time.Minute}})
+
+	resp := sendRequest(t, oapi, "POST", "/users/bob", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+	assert.Equal(t, "1", resp.Header.Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+	assert.Empty(t, resp.Header.Get("Retry-After"))
+
+	// Invalid requests still consume a token: the limit applies before parsing.
+	resp = sendRequest(t, oapi, "POST", "/users/a", "")
+	require.Equal(t, 422, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+	resp = sendRequest(t, oapi, "POST", "/users/bob", "")
+	require.Equal(t, 429, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get("Retry-After"))
+	assert.Equal(t, "60", resp.Header.Get("RateLimit-Reset"))
+
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, errTypeRateLimited, env.Errors[0].Type)
+	assert.Equal(t, 429, env.Errors[0].Code)
+	assert.Equal(t, "2;w=60", env.Errors[0].Constraint)
+	assert.Equal(t, "rate limit of 2 requests per 1m0s exceeded; retry in 30s", env.Errors[0].Msg)
+}
+
+func TestRateLimit_ByAPIKey(t *testing.T) {
+	oapi := newTestApp()
+	Get(oapi, "/reports", getReport, OpenAPIOptions{RateLimit: &RateLimit{Requests: 1, Window: time.Hour, Key: RateLimitByAPIKey}})
+	first := sendRequest(t, oapi, "GET", "/reports", "", "X-API-Key", "k1")
+	second := sendRequest(t, oapi, "GET", "/reports", "", "X-API-Key", "k1")
+	other := sendRequest(t, oapi, "GET", "/reports", "", "X-API-Key", "k2")
+	assert.Equal(t, []int{200, 429, 200}, []int{first.StatusCode, second.StatusCode, other.StatusCode})
+}
+
+func TestRateLimit_ByUser(t *testing.T) {
+	oapi := newTestApp(Config{
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+	})
+	Get(oapi, "/me", func(c fiber.Ctx, input struct{}) (uniOutput, error) {
+		return uniOutput{Message: "ok"}, nil
+	}, OpenAPIOptions{RateLimit: &RateLimit{Requests: 1, Window: time.Minute, Key: RateLimitByUser}})
+
+	bearer := func(token string) []string { return []string{"Authorization", "Bearer " + token} }
+	a := sendRequest(t, oapi, "GET", "/me", "", bearer("admin-token")...)
+	b := sendRequest(t, oapi, "GET", "/me", "", bearer("admin-token")...)
+	c := sendRequest(t, oapi, "GET", "/me", "", bearer("valid-token")...)
+	assert.Equal(t, []int{200, 429, 200}, []int{a.StatusCode, b.StatusCode, c.StatusCode})
+
+	// Rejected credentials are charged to the client IP.
+	d := sendRequest(t, oapi, "GET", "/me", "", bearer("nope")...)
+	assert.Equal(t, 401, d.StatusCode)
+	assert.Equal(t, "0", d.Header.Get("RateLimit-Remaining"))
+	e := sendRequest(t, oapi, "GET", "/me", "", bearer("nope")...)
+	assert.Equal(t, 429, e.StatusCode)
+}
+
+func TestRateLimit_ByUserChargesInvalidRequests(t *testing.T) {
+	oapi := newTestApp(Config{
+		EnableValidation:    true,
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+	})
+	Post(oapi, "/users/:name", createUniUser, OpenAPIOptions{RateLimit: &RateLimit{Requests: 3, Window: time.Minute, Key: RateLimitByUser}})
+
+	// A flood of 422s (too short a name) is cut off.
+	auth := []string{"Authorization", "Bearer admin-token"}
+	var statuses []int
+	for range 4 {
+		resp := sendRequest(t, oapi, "POST", "/users/a", "", auth...)
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{422, 422, 422, 429}, statuses)
+
+	// The IP bucket is spent, the user's own is untouched.
+	resp := sendRequest(t, oapi, "POST", "/users/bob", "", auth...)
+	assert.Equal(t, 200, resp.StatusCode)
+	resp = sendRequest(t, oapi, "POST", "/users/bob", "")
+	assert.Equal(t, 429, resp.StatusCode)
+}
+
+func TestRateLimit_ByUserChargesForbiddenRequestsToTheUser(t *testing.T) {
+	oapi := newTestApp(Config{
+		EnableAuthorization: true,
+		AuthService:         NewMockAuthService(),
+		SecuritySchemes: map[string]SecurityScheme{
+			"bearerAuth": {Type: "http", Scheme: "bearer"},
+		},
+		DefaultSecurity: []map[string][]string{{"bearerAuth": {}}},
+	})
+	Get(oapi, "/admin", getReport, WithRoles(OpenAPIOptions{RateLimit: &RateLimit{Requests: 1, Window: time.Minute, Key: RateLimitByUser}}, "admin"))
+
+	// The 403 comes after authentication, so the user's bucket pays for it.
+	resp := sendRequest(t, oapi, "GET", "/admin", "", "Authorization", "Bearer valid-token")
+	require.Equal(t, 403, resp.StatusCode)
+	assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+	resp = sendRequest(t, oapi, "GET", "/admin", "", "Authorization", "Bearer valid-token")
+	assert.Equal(t, 429, resp.StatusCode)
+
+	// The IP bucket is untouched.
+	resp = sendRequest(t, oapi, "GET", "/admin", "")
+	assert.Equal(t, 401, resp.StatusCode)
+}
+
+func TestRateLimit_ProblemDetails(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	Get(oapi, "/reports", getReport, OpenAPIOptions{RateLimit: &RateLimit{Requests: 1, Window: time.Hour, Key: RateLimitByAPIKey}})
+	sendRequest(t, oapi, "GET", "/reports", "")
+	resp := sendRequest(t, oapi, "GET", "/reports", "")
+	require.Equal(t, 429, resp.StatusCode)
+	p := resp.problem(t)
+	assert.Equal(t, 429, p.Status)
+	assert.Equal(t, errTypeRateLimited, p.Errors[0].Type)
+}
+
+func TestRateLimit_FailingStoreAllowsRequests(t *testing.T) {
+	oapi := newTestApp(Config{RateLimitStore: failingStore{}})
+	Get(oapi, "/reports", getReport, OpenAPIOptions{RateLimit: &RateLimit{Requests: 1, Window: time.Hour, Key: RateLimitByAPIKey}})
+	for range 3 {
+		resp := sendRequest(t, oapi, "GET", "/reports", "")
+		assert.Equal(t, 200, resp.StatusCode)
+	}
+}
+
+func TestRateLimit_Spec(t *testing.T) {
+	oapi := newTestApp()
+	Get(oapi, "/reports", func(c fiber.Ctx, in struct{}) (uniOutput, error) {
+		return uniOutput{}, nil
+	}, OpenAPIOptions{RateLimit: &RateLimit{Requests: 100, Window: time.Hour, Key: RateLimitByAPIKey, Header: "X-Token"}})
+	Get(oapi, "/free", func(c fiber.Ctx, in struct{}) (uniOutput, error) {
+		return uniOutput{}, nil
+	}, OpenAPIOptions{})
+
+	spec := oapi.GenerateOpenAPISpec()
+	op := spec["paths"].(map[string]interface{})["/reports"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"requests": 100, "window": 3600, "key": "api_key", "header": "X-Token"}, op["x-rate-limit"])
+
+	resp := op["responses"].(map[string]interface{})["429"].(map[string]interface{})
+	assert.Equal(t, "Rate limit exceeded", resp["description"])
+	headers := resp["headers"].(map[string]interface{})
+	for _, h := range []string{"Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset"} {
+		assert.Contains(t, headers, h)
+	}
+	content := resp["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	example := content["example"].(ErrorEnvelope)
+	assert.Equal(t, errTypeRateLimited, example.Errors[0].Type)
+
+	free := spec["paths"].(map[string]interface{})["/free"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.NotContains(t, free, "x-rate-limit")
+	assert.NotContains(t, free["responses"], "429")
+}
+
+func TestRateLimit_InvalidDeclarationPanics(t *testing.T) {
+	oapi := newTestApp()
+	assert.Panics(t, func() {
+		Get(oapi, "/x", func(c fiber.Ctx, in struct{}) (uniOutput, error) {
+			return uniOutput{}, nil
+		}, OpenAPIOptions{RateLimit: &RateLimit{Requests: 10}})
+	})
+}
@@ -0,0 +1,83 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// errTypeInternal is the entry type of the 500 served for a recovered panic.
+const errTypeInternal = "internal_error"
+
+// internalErrorMsg is the only message a recovered panic exposes to clients;
+// the panic value itself is reported to the PanicHandler.
+const internalErrorMsg = "internal server error"
+
+// PanicHandler receives a panic recovered from an operation (see
+// Config.RecoverPanics) with the stack of the panicking goroutine. It must not
+// write the response.
+type PanicHandler func(c fiber.Ctx, recovered any, stack []byte)
+
+// defaultPanicHandler logs the panic as a structured error.
+func defaultPanicHandler(c fiber.Ctx, recovered any, stack []byte) {
+	slog.Error("fiberoapi: recovered panic in handler",
+		"method", c.Method(),
+		"path", c.Path(),
+		"panic", fmt.Sprint(recovered),
+		"stack", string(stack),
+	)
+}
+
+// recoverPanics wraps an operation handler so that a panic in parsing, in the
+// handler or in serialization becomes a 500 internal_error in the configured
+// error shape. The response only keeps the headers set before the operation
+// ran (request ID, CORS...), not those of the handler.
+func (o *OApiApp) recoverPanics(next fiber.Handler) fiber.Handler {
+	return func(c fiber.Ctx) (err error) {
+		headers := c.GetRespHeaders()
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			report := o.config.PanicHandler
+			if report == nil {
+				report = defaultPanicHandler
+			}
+			report(c, recovered, debug.Stack())
+
+			// Drop whatever the handler may have written before panicking.
+			c.Response().Reset()
+			for name, values := range headers {
+				for _, v := range values {
+					c.Response().Header.Add(name, v)
+				}
+			}
+			err = o.writeErrorCategory(c, internalErrorCategory())
+		}()
+		return next(c)
+	}
+}
+
+func internalErrorCategory() errorCategory {
+	return errorCategory{
+		Code:    fiber.StatusInternalServerError,
+		Type:    errTypeInternal,
+		Message: internalErrorMsg,
+	}
+}
+
+func internalErrorEntry() ValidationErrorEntry {
+	return ValidationErrorEntry{
+		Type: errTypeInternal,
+		Code: fiber.StatusInternalServerError,
+		Loc:  []any{},
+		Msg:  internalErrorMsg,
+	}
+}
+
+func exampleInternalErrorEnvelope() ErrorEnvelope {
+	return exampleEnvelope(internalErrorEntry())
+}
@@ -0,0 +1,100 @@
+package fiberoapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicOutput struct {
+	OK bool `json:"ok"`
+}
+
+func panickingHandler(c fiber.Ctx, in struct{}) (panicOutput, error) {
+	c.WriteString("partial")
+	panic("database handle is nil")
+}
+
+func TestRecoverPanics_Envelope(t *testing.T) {
+	var recovered any
+	var stack []byte
+	oapi := newTestApp(Config{
+		RecoverPanics: true,
+		PanicHandler: func(c fiber.Ctx, r any, s []byte) {
+			recovered, stack = r, s
+		},
+	})
+	Get(oapi, "/boom", panickingHandler, OpenAPIOptions{})
+	Get(oapi, "/fine", func(c fiber.Ctx, in struct{}) (panicOutput, error) {
+		return panicOutput{OK: true}, nil
+	}, OpenAPIOptions{})
+
+	resp := sendRequest(t, oapi, "GET", "/boom", "", "X-Request-Id", "req-123")
+	require.Equal(t, 500, resp.StatusCode)
+
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "internal_error", env.Errors[0].Type)
+	assert.Equal(t, 500, env.Errors[0].Code)
+	assert.Equal(t, "internal server error", env.Errors[0].Msg, "the panic value is not exposed")
+	assert.Equal(t, "req-123", env.ResponseContext.ResponseID)
+
+	assert.Equal(t, "database handle is nil", recovered)
+	assert.True(t, strings.Contains(string(stack), "recover_test.go"), "stack points at the panicking handler")
+
+	resp = sendRequest(t, oapi, "GET", "/fine", "")
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestRecoverPanics_DropsHandlerHeaders(t *testing.T) {
+	oapi := newTestApp(Config{RecoverPanics: true, PanicHandler: func(fiber.Ctx, any, []byte) {}})
+	oapi.FiberApp().Use(RequestIDMiddleware())
+	Get(oapi, "/boom", func(c fiber.Ctx, in struct{}) (panicOutput, error) {
+		c.Set("X-Cache", "hit")
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Status(201)
+		return panickingHandler(c, in)
+	}, OpenAPIOptions{})
+
+	resp := sendRequest(t, oapi, "GET", "/boom", "", "X-Request-Id", "req-123")
+	require.Equal(t, 500, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("X-Cache"))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderContentType), "application/json")
+	assert.Equal(t, "req-123", resp.Header.Get("X-Request-Id"), "middleware headers are kept")
+	assert.Equal(t, "req-123", resp.envelope(t).ResponseContext.ResponseID)
+}
+
+func TestRecoverPanics_DefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{
+		RecoverPanics:     true,
+		PanicHandler:      func(fiber.Ctx, any, []byte) {},
+		DefaultErrorShape: &UniErr{},
+	})
+	Get(oapi, "/boom", panickingHandler, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "GET", "/boom", "")
+	require.Equal(t, 500, resp.StatusCode)
+
+	var body UniErr
+	resp.decode(t, &body)
+	assert.Equal(t, "internal_error", body.Type)
+	assert.Equal(t, "internal server error", body.Message)
+}
+
+func TestRecoverPanics_Spec(t *testing.T) {
+	responsesOf := func(cfg Config) map[string]interface{} {
+		oapi := newTestApp(cfg)
+		Get(oapi, "/boom", panickingHandler, OpenAPIOptions{})
+		op := oapi.GenerateOpenAPISpec()["paths"].(map[string]interface{})["/boom"].(map[string]interface{})["get"].(map[string]interface{})
+		return op["responses"].(map[string]interface{})
+	}
+
+	responses := responsesOf(Config{RecoverPanics: true})
+	require.Contains(t, responses, "500")
+	media := responses["500"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	assert.Equal(t, exampleInternalErrorEnvelope(), media["example"])
+
+	assert.NotContains(t, responsesOf(Config{}), "500")
+}
@@ -0,0 +1,158 @@
+package fiberoapi
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDLocalsKey = "fiberoapi.requestid"
+	traceLocalsKey     = "fiberoapi.trace"
+	traceparentHeader  = "traceparent"
+)
+
+// RequestIDConfig configures RequestIDMiddleware.
+type RequestIDConfig struct {
+	Header    string        // Request and response header carrying the ID (default: "X-Request-Id")
+	Generator func() string // Generates missing IDs (default: NewUUIDv7; NewULID is the alternative)
+}
+
+// TraceContext is the W3C trace context of a request, parsed from its
+// traceparent header.
+type TraceContext struct {
+	TraceID string // 32 lowercase hex digits
+	SpanID  string // 16 lowercase hex digits: the caller's span, parent of this request
+	Sampled bool   // trace-flags sampled bit
+}
+
+// RequestIDMiddleware gives every request an ID: the incoming header when it
+// is a safe opaque token, a generated one otherwise. The ID is echoed in the
+// response header, stored for RequestIDFromCtx and reported in the
+// ResponseContext of every error the library writes, together with the trace
+// and span IDs of a valid traceparent header.
+//
+//	app.Use(fiberoapi.RequestIDMiddleware())
+//	app.Use(fiberoapi.RequestIDMiddleware(fiberoapi.RequestIDConfig{Generator: fiberoapi.NewULID}))
+func RequestIDMiddleware(cfg ...RequestIDConfig) fiber.Handler {
+	var config RequestIDConfig
+	if len(cfg) > 0 {
+		config = cfg[0]
+	}
+	if config.Header == "" {
+		config.Header = requestIDHeader
+	}
+	if config.Generator == nil {
+		config.Generator = NewUUIDv7
+	}
+	return func(c fiber.Ctx) error {
+		id := sanitizeRequestID(c.Get(config.Header))
+		if id == "" {
+			id = config.Generator()
+		}
+		c.Locals(requestIDLocalsKey, id)
+		c.Set(config.Header, id)
+		if tc, ok := parseTraceparent(c.Get(traceparentHeader)); ok {
+			c.Locals(traceLocalsKey, tc)
+		}
+		return c.Next()
+	}
+}
+
+// RequestIDFromCtx returns the ID RequestIDMiddleware assigned to the request,
+// or "" when the middleware is not installed.
+func RequestIDFromCtx(c fiber.Ctx) string {
+	id, _ := c.Locals(requestIDLocalsKey).(string)
+	return id
+}
+
+// TraceFromCtx returns the trace context RequestIDMiddleware parsed from the
+// request's traceparent header.
+func TraceFromCtx(c fiber.Ctx) (TraceContext, bool) {
+	tc, ok := c.Locals(traceLocalsKey).(TraceContext)
+	return tc, ok
+}
+
+// responseContext builds the ResponseContext of an error response: the
+// middleware-assigned request ID and trace when available, otherwise the
+// sanitized X-Request-Id header.
+func responseContext(c fiber.Ctx) ResponseContext {
+	rc := ResponseContext{ResponseID: RequestIDFromCtx(c)}
+	if rc.ResponseID == "" {
+		rc.ResponseID = sanitizeRequestID(c.Get(requestIDHeader))
+	}
+	if tc, ok := TraceFromCtx(c); ok {
+		rc.TraceID = tc.TraceID
+		rc.SpanID = tc.SpanID
+	}
+	return rc
+}
+
+// parseTraceparent parses a version 00 traceparent header
+// ("00-<trace-id>-<parent-id>-<flags>"). Higher versions are read by the same
+// prefix, as the W3C spec requires; all-zero IDs and version ff are invalid.
+func parseTraceparent(header string) (TraceContext, bool) {
+	header = strings.TrimSpace(header)
+	if len(header) < 55 || (len(header) > 55 && header[55] != '-') {
+		return TraceContext{}, false
+	}
+	parts := strings.Split(header[:55], "-")
+	if len(parts) != 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return TraceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if version == "ff" || (version == "00" && len(header) != 55) {
+		return TraceContext{}, false
+	}
+	for _, p := range parts {
+		if !isLowerHex(p) {
+			return TraceContext{}, false
+		}
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return TraceContext{}, false
+	}
+	f, _ := hex.DecodeString(flags)
+	return TraceContext{TraceID: traceID, SpanID: spanID, Sampled: f[0]&1 == 1}, true
+}
+
+func isLowerHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// NewUUIDv7 returns a time-ordered RFC 9562 UUID version 7.
+func NewUUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a ULID: 48 bits of millisecond timestamp and 80 random bits
+// in 26 Crockford base32 characters, lexicographically sortable by time.
+func NewULID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits as 26 five-bit groups, the first one holding only 3 bits.
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
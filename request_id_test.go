@@ -0,0 +1,128 @@
+package fiberoapi
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	uuidV7Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ulidPattern   = regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+)
+
+const sampleTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func echoRequestID(c fiber.Ctx, in uniInput) (uniOutput, error) {
+	tc, _ := TraceFromCtx(c)
+	return uniOutput{Message: RequestIDFromCtx(c) + "|" + tc.TraceID}, nil
+}
+
+func TestRequestID_GeneratedAndEchoed(t *testing.T) {
+	oapi := newTestApp()
+	oapi.FiberApp().Use(RequestIDMiddleware())
+	Post(oapi, "/users/:name", echoRequestID, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{}`)
+	require.Equal(t, 422, resp.StatusCode)
+
+	id := resp.Header.Get("X-Request-Id")
+	assert.Regexp(t, uuidV7Pattern, id)
+	env := resp.envelope(t)
+	assert.Equal(t, id, env.ResponseContext.ResponseID)
+	assert.Empty(t, env.ResponseContext.TraceID)
+}
+
+func TestRequestID_KeepsSafeIncomingID(t *testing.T) {
+	oapi := newTestApp()
+	oapi.FiberApp().Use(RequestIDMiddleware())
+	Post(oapi, "/users/:name", echoRequestID, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/bob", `{}`, "X-Request-Id", "abc-123")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "abc-123", resp.Header.Get("X-Request-Id"))
+	assert.Contains(t, string(resp.body), "abc-123|")
+
+	// An unsafe value is replaced rather than echoed.
+	resp = sendRequest(t, oapi, "POST", "/users/bob", `{}`, "X-Request-Id", "bad value\tx")
+	assert.Regexp(t, uuidV7Pattern, resp.Header.Get("X-Request-Id"))
+}
+
+func TestRequestID_CustomHeaderAndULID(t *testing.T) {
+	oapi := newTestApp()
+	oapi.FiberApp().Use(RequestIDMiddleware(RequestIDConfig{Header: "X-Correlation-Id", Generator: NewULID}))
+	oapi.UseNotFoundHandler()
+	resp := sendRequest(t, oapi, "GET", "/nowhere", "")
+	require.Equal(t, 404, resp.StatusCode)
+
+	id := resp.Header.Get("X-Correlation-Id")
+	assert.Regexp(t, ulidPattern, id)
+	assert.Equal(t, id, resp.envelope(t).ResponseContext.ResponseID)
+}
+
+func TestRequestID_TraceContext(t *testing.T) {
+	oapi := newTestApp()
+	oapi.FiberApp().Use(RequestIDMiddleware())
+	Post(oapi, "/users/:name", echoRequestID, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{}`, "traceparent", sampleTraceparent)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", env.ResponseContext.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", env.ResponseContext.SpanID)
+
+	resp = sendRequest(t, oapi, "POST", "/users/bob", `{}`, "traceparent", sampleTraceparent)
+	assert.Contains(t, string(resp.body), "|4bf92f3577b34da6a3ce929d0e0e4736")
+}
+
+func TestRequestID_ProblemDetails(t *testing.T) {
+	oapi := newTestApp(Config{ProblemDetails: true})
+	oapi.FiberApp().Use(RequestIDMiddleware())
+	Post(oapi, "/users/:name", echoRequestID, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{}`, "traceparent", sampleTraceparent)
+	require.Equal(t, 422, resp.StatusCode)
+	p := resp.problem(t)
+	assert.Equal(t, resp.Header.Get("X-Request-Id"), p.RequestID)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", p.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", p.SpanID)
+}
+
+func TestRequestID_WithoutMiddlewareMirrorsHeader(t *testing.T) {
+	oapi := newTestApp()
+	Post(oapi, "/users/:name", echoRequestID, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/users/a", `{}`, "X-Request-Id", "abc-123", "traceparent", sampleTraceparent)
+	require.Equal(t, 422, resp.StatusCode)
+	assert.Equal(t, ResponseContext{ResponseID: "abc-123"}, resp.envelope(t).ResponseContext)
+}
+
+func TestParseTraceparent(t *testing.T) {
+	tc, ok := parseTraceparent(sampleTraceparent)
+	require.True(t, ok)
+	assert.Equal(t, TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}, tc)
+
+	tc, ok = parseTraceparent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	require.True(t, ok, "higher versions are parsed by prefix")
+	assert.False(t, tc.Sampled)
+
+	for _, bad := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736x00f067aa0ba902b7-01",
+	} {
+		_, ok := parseTraceparent(bad)
+		assert.False(t, ok, bad)
+	}
+}
+
+func TestIDGenerators(t *testing.T) {
+	a, b := NewULID(), NewULID()
+	assert.Regexp(t, ulidPattern, a)
+	assert.NotEqual(t, a, b)
+	assert.LessOrEqual(t, a[:10], b[:10], "ULIDs sort by time")
+	assert.Regexp(t, uuidV7Pattern, NewUUIDv7())
+}
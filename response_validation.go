@@ -0,0 +1,392 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+)
+
+// ResponseValidationMode selects what the Method wrapper does with handler
+// outputs that do not match the declared output type. It is meant for tests
+// and staging: every checked response pays a JSON round-trip plus a schema walk.
+type ResponseValidationMode int
+
+const (
+	// ResponseValidationOff sends outputs unchecked (default).
+	ResponseValidationOff ResponseValidationMode = iota
+	// ResponseValidationLog reports violations to Config.ResponseViolationHandler
+	// (a structured slog warning by default) and still sends the response.
+	ResponseValidationLog
+	// ResponseValidationReject reports violations like ResponseValidationLog,
+	// then replaces the response with a 500 carrying one
+	// "response_validation_error" entry per violation (or the DefaultErrorShape).
+	ResponseValidationReject
+)
+
+const errTypeResponseValidation = "response_validation_error"
+
+// ResponseViolation describes a handler output that does not match the
+// schema documented for its operation. Entries use the envelope entry shape,
+// with loc rooted at "response" (e.g. ["response", "items", 2, "id"]).
+type ResponseViolation struct {
+	Method      string // HTTP method
+	Path        string // route template, e.g. /users/:id
+	OperationID string
+	Status      int // status the handler response would have been sent with
+	Errors      []ValidationErrorEntry
+}
+
+// ResponseViolationHandler receives the violations found by response
+// validation. It must not write the response.
+type ResponseViolationHandler func(c fiber.Ctx, v ResponseViolation)
+
+// defaultResponseViolationHandler logs one structured warning per response.
+func defaultResponseViolationHandler(c fiber.Ctx, v ResponseViolation) {
+	msgs := make([]string, len(v.Errors))
+	for i, e := range v.Errors {
+		msgs[i] = e.Msg
+	}
+	slog.Warn("fiberoapi: response does not match the declared schema",
+		"method", v.Method,
+		"path", v.Path,
+		"operationId", v.OperationID,
+		"status", v.Status,
+		"violations", msgs,
+	)
+}
+
+// responseChecker validates the outputs of one operation. The output schema
+// and the components it references are built lazily on first use; mock mode
+// reuses them to synthesise payloads.
+type responseChecker struct {
+	op         OpenAPIOperation
+	rules      *validationRules // the app's validator and custom tag schemas
+	once       sync.Once
+	schema     map[string]interface{}
+	components map[string]interface{}
+}
+
+func (r *responseChecker) init() {
+	types := make(map[string]reflect.Type)
+	collectAllTypes(r.op.OutputType, types)
+	r.components = make(map[string]interface{}, len(types))
+	for name, t := range types {
+		r.components[name] = generateSchema(t, r.rules)
+	}
+	r.schema = generateSchema(r.op.OutputType, r.rules)
+}
+
+// check returns the violations found in output, or nil when it conforms (or
+// cannot be serialized — the regular serialization error path handles that).
+func (r *responseChecker) check(output any) []ValidationErrorEntry {
+	r.once.Do(r.init)
+
+	var entries []ValidationErrorEntry
+	seen := map[string]bool{}
+	add := func(loc []any, field, msg, constraint string) {
+		key := fmt.Sprint(loc)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		entries = append(entries, ValidationErrorEntry{
+			Type:       errTypeResponseValidation,
+			Code:       fiber.StatusInternalServerError,
+			Loc:        loc,
+			Field:      field,
+			Msg:        msg,
+			Constraint: constraint,
+		})
+	}
+
+	// Struct validation: honours `validate` tags on the output type, which are
+	// otherwise only used to document the schema.
+	r.validateValue(reflect.ValueOf(output), []any{"response"}, add, 0)
+
+	// Schema conformance of what would actually go on the wire.
+	data, err := json.Marshal(output)
+	if err != nil {
+		return entries
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return entries
+	}
+	r.walk(r.schema, decoded, []any{"response"}, true, add, 0)
+	return entries
+}
+
+// validateValue runs the `validate` tags of v, or of the structs it holds when
+// v is a slice, array or map. The validator only dives into containers whose
+// field carries a dive tag, so they are walked here and each element is
+// reported under its index or key.
+func (r *responseChecker) validateValue(v reflect.Value, loc []any, add func([]any, string, string, string), depth int) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() || depth > 32 {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var vErrs validator.ValidationErrors
+		if err := r.rules.validate.Struct(v.Interface()); errors.As(err, &vErrs) {
+			resolver := resolverFor(v.Type())
+			for _, fe := range vErrs {
+				rel, leaf := resolver.resolve(fe.StructNamespace())
+				if leaf == "" {
+					leaf = fe.Field()
+				}
+				at := loc
+				if len(rel) > 1 {
+					// rel[0] is the request source; the fields follow it.
+					at = append(append([]any{}, loc...), rel[1:]...)
+				}
+				add(at, leaf, translateValidatorTag(leaf, fe.ActualTag(), fe.Param()), constraintString(fe.ActualTag(), fe.Param()))
+			}
+		}
+		r.validateFields(v, loc, add, depth)
+	case reflect.Slice, reflect.Array:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			r.validateValue(v.Index(i), appendLoc(loc, i), add, depth+1)
+		}
+	case reflect.Map:
+		if !mayHoldStructs(v.Type().Elem()) {
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			r.validateValue(v.MapIndex(k), appendLoc(loc, fmt.Sprint(k)), add, depth+1)
+		}
+	}
+}
+
+// validateFields visits the container fields of a struct that the validator
+// has already checked, including those of its nested structs.
+func (r *responseChecker) validateFields(v reflect.Value, loc []any, add func([]any, string, string, string), depth int) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" || strings.Contains(field.Tag.Get("validate"), "dive") {
+			continue
+		}
+		fv := v.Field(i)
+		for fv.Kind() == reflect.Ptr || fv.Kind() == reflect.Interface {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		at := loc
+		if !field.Anonymous || field.Tag.Get("json") != "" {
+			at = appendLoc(loc, jsonFieldName(field))
+		}
+		switch fv.Kind() {
+		case reflect.Struct:
+			if depth < 32 {
+				r.validateFields(fv, at, add, depth+1)
+			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			r.validateValue(fv, at, add, depth+1)
+		}
+	}
+}
+
+// mayHoldStructs reports whether values of t can contain structs to validate.
+func mayHoldStructs(t reflect.Type) bool {
+	switch dereferenceType(t).Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// walk checks value against schema and reports mismatches through add. It
+// covers the keywords the spec generator emits: type, enum, minimum / maximum,
+// minLength / maxLength, required, properties, items and additionalProperties.
+// null is accepted for properties that are not required, since optional
+// pointer fields serialise as null. Properties are visited in sorted order so
+// the reported entries are stable.
+func (r *responseChecker) walk(schema map[string]interface{}, value any, loc []any, required bool, add func([]any, string, string, string), depth int) {
+	if schema == nil || depth > 32 {
+		return
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		target, _ := r.components[name].(map[string]interface{})
+		r.walk(target, value, loc, required, add, depth+1)
+		return
+	}
+
+	field := ""
+	if len(loc) > 1 {
+		field = fmt.Sprint(loc[len(loc)-1])
+	}
+	want, _ := schema["type"].(string)
+	if value == nil {
+		if want != "" && required {
+			add(loc, field, fmt.Sprintf("expected %s but got null", want), "type="+want)
+		}
+		return
+	}
+	if want != "" && !jsonValueIs(value, want) {
+		add(loc, field, fmt.Sprintf("expected %s but got %s", want, jsonTypeName(value)), "type="+want)
+		return
+	}
+	if enum, ok := schema["enum"].([]string); ok && !contains(enum, fmt.Sprint(value)) {
+		add(loc, field, fmt.Sprintf("value must be one of: %s", strings.Join(enum, ", ")), "oneof="+strings.Join(enum, " "))
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if min, ok := schema["minimum"].(int); ok && v < float64(min) {
+			add(loc, field, fmt.Sprintf("value must be at least %d", min), fmt.Sprintf("min=%d", min))
+		}
+		if max, ok := schema["maximum"].(int); ok && v > float64(max) {
+			add(loc, field, fmt.Sprintf("value must be at most %d", max), fmt.Sprintf("max=%d", max))
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if min, ok := schema["minLength"].(int); ok && n < min {
+			add(loc, field, fmt.Sprintf("length must be at least %d", min), fmt.Sprintf("min=%d", min))
+		}
+		if max, ok := schema["maxLength"].(int); ok && n > max {
+			add(loc, field, fmt.Sprintf("length must be at most %d", max), fmt.Sprintf("max=%d", max))
+		}
+	case map[string]any:
+		req, _ := schema["required"].([]string)
+		for _, name := range req {
+			if _, ok := v[name]; !ok {
+				add(appendLoc(loc, name), name, fmt.Sprintf("field '%s' is required", name), "required")
+			}
+		}
+		props, _ := schema["properties"].(map[string]interface{})
+		for _, name := range sortedKeys(props) {
+			val, ok := v[name]
+			if !ok {
+				continue
+			}
+			subSchema, _ := props[name].(map[string]interface{})
+			r.walk(subSchema, val, appendLoc(loc, name), contains(req, name), add, depth+1)
+		}
+		if extra, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			for _, name := range sortedKeys(v) {
+				if _, declared := props[name]; !declared {
+					r.walk(extra, v[name], appendLoc(loc, name), false, add, depth+1)
+				}
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range v {
+			r.walk(items, item, appendLoc(loc, i), true, add, depth+1)
+		}
+	}
+}
+
+func appendLoc(loc []any, seg any) []any {
+	out := make([]any, len(loc), len(loc)+1)
+	copy(out, loc)
+	return append(out, seg)
+}
+
+// jsonValueIs reports whether a decoded JSON value matches a schema type.
+func jsonValueIs(v any, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	default:
+		return true
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch t := v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if t == math.Trunc(t) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return "null"
+	}
+}
+
+// validateResponse runs the configured response validation for one handler
+// output. It returns handled=true when it has written the replacement 500
+// response (ResponseValidationReject) and the caller must not send output.
+func (o *OApiApp) validateResponse(c fiber.Ctx, checker *responseChecker, output any) (handled bool, err error) {
+	entries := checker.check(output)
+	if len(entries) == 0 {
+		return false, nil
+	}
+
+	violation := ResponseViolation{
+		Method:      checker.op.Method,
+		Path:        checker.op.Path,
+		OperationID: checker.op.Options.OperationID,
+		Status:      c.Response().StatusCode(),
+		Errors:      entries,
+	}
+	report := o.config.ResponseViolationHandler
+	if report == nil {
+		report = defaultResponseViolationHandler
+	}
+	report(c, violation)
+
+	if o.config.ResponseValidation != ResponseValidationReject {
+		return false, nil
+	}
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Msg
+	}
+	return true, o.sendError(c, fiber.StatusInternalServerError, errorCategory{
+		Code:    fiber.StatusInternalServerError,
+		Type:    errTypeResponseValidation,
+		Message: "response does not match the declared schema",
+		Details: strings.Join(msgs, "; "),
+	}, entries)
+}
@@ -0,0 +1,200 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rvItem struct {
+	ID    string `json:"id" validate:"required"`
+	Price int    `json:"price" validate:"min=0"`
+}
+
+type rvOutput struct {
+	Status string   `json:"status" validate:"required,oneof=active archived"`
+	Items  []rvItem `json:"items"`
+	Note   *string  `json:"note"`
+}
+
+// rvRaw serialises as something that is not an rvOutput at all, the way a
+// handler might accidentally return an error payload with a 200.
+type rvRaw struct {
+	Status string   `json:"status"`
+	Items  []rvItem `json:"items"`
+}
+
+func (rvRaw) MarshalJSON() ([]byte, error) {
+	return []byte(`{"code":500,"status":7,"items":"oops"}`), nil
+}
+
+type rvInput struct {
+	Case string `query:"case"`
+}
+
+func TestResponseValidation_Off(t *testing.T) {
+	app := fiber.New()
+	called := false
+	oapi := New(app, Config{ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) { called = true }})
+	Get(oapi, "/orders", func(c fiber.Ctx, in struct{}) (rvOutput, error) {
+		return rvOutput{}, nil
+	}, OpenAPIOptions{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.False(t, called)
+}
+
+func TestResponseValidation_LogReportsAndSends(t *testing.T) {
+	app := fiber.New()
+	var got []ResponseViolation
+	oapi := New(app, Config{
+		ResponseValidation:       ResponseValidationLog,
+		ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) { got = append(got, v) },
+	})
+	Get(oapi, "/orders", func(c fiber.Ctx, in rvInput) (rvOutput, error) {
+		if in.Case == "valid" {
+			return rvOutput{Status: "active", Items: []rvItem{{ID: "a"}}}, nil
+		}
+		return rvOutput{Status: "deleted", Items: []rvItem{{ID: "a"}, {Price: -1}}}, nil
+	}, OpenAPIOptions{OperationID: "listOrders"})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders?case=valid", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Empty(t, got)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode, "log mode never changes the response")
+	require.Len(t, got, 1)
+
+	v := got[0]
+	assert.Equal(t, "GET", v.Method)
+	assert.Equal(t, "/orders", v.Path)
+	assert.Equal(t, "listOrders", v.OperationID)
+	assert.Equal(t, 200, v.Status)
+
+	locs := map[string]ValidationErrorEntry{}
+	for _, e := range v.Errors {
+		assert.Equal(t, "response_validation_error", e.Type)
+		raw, _ := json.Marshal(e.Loc)
+		locs[string(raw)] = e
+	}
+	assert.Contains(t, locs, `["response","status"]`)
+	assert.Equal(t, "oneof=active archived", locs[`["response","status"]`].Constraint)
+	// Validate tags apply to slice elements, reported under their index.
+	assert.Equal(t, "field 'price' must be at least 0", locs[`["response","items",1,"price"]`].Msg)
+	assert.Equal(t, "required", locs[`["response","items",1,"id"]`].Constraint)
+}
+
+func TestResponseValidation_ValidatesContainerElements(t *testing.T) {
+	var got []ResponseViolation
+	oapi := newTestApp(Config{
+		ResponseValidation:       ResponseValidationLog,
+		ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) { got = append(got, v) },
+	})
+	Get(oapi, "/items", func(c fiber.Ctx, in struct{}) ([]rvItem, error) {
+		return []rvItem{{ID: "a"}, {ID: "b", Price: -1}}, nil
+	}, OpenAPIOptions{})
+	Get(oapi, "/pages", func(c fiber.Ctx, in struct{}) ([][]*rvItem, error) {
+		return [][]*rvItem{{{ID: "a"}}, {{ID: "a"}, {ID: "b"}, {}}}, nil
+	}, OpenAPIOptions{})
+	Get(oapi, "/by-id", func(c fiber.Ctx, in struct{}) (map[string]rvItem, error) {
+		return map[string]rvItem{"a": {ID: "a"}, "b": {Price: 1}}, nil
+	}, OpenAPIOptions{})
+
+	locs := func(path string) []string {
+		t.Helper()
+		got = nil
+		resp := sendRequest(t, oapi, "GET", path, "")
+		require.Equal(t, 200, resp.StatusCode)
+		require.Len(t, got, 1)
+		var out []string
+		for _, e := range got[0].Errors {
+			raw, _ := json.Marshal(e.Loc)
+			out = append(out, string(raw))
+		}
+		return out
+	}
+	assert.Equal(t, []string{`["response",1,"price"]`}, locs("/items"))
+	assert.Equal(t, []string{`["response",1,2,"id"]`}, locs("/pages"))
+	assert.Equal(t, []string{`["response","b","id"]`}, locs("/by-id"))
+}
+
+func TestResponseValidation_RejectReplacesWith500(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app, Config{
+		ResponseValidation:       ResponseValidationReject,
+		ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) {},
+	})
+	Get(oapi, "/raw", func(c fiber.Ctx, in struct{}) (rvRaw, error) {
+		return rvRaw{}, nil
+	}, OpenAPIOptions{})
+
+	req := httptest.NewRequest("GET", "/raw", nil)
+	req.Header.Set("X-Request-Id", "req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, 500, resp.StatusCode)
+
+	raw, _ := io.ReadAll(resp.Body)
+	var env ErrorEnvelope
+	require.NoError(t, json.Unmarshal(raw, &env), "%s", raw)
+	assert.Equal(t, "req-1", env.ResponseContext.ResponseID)
+
+	msgs := map[string]string{}
+	for _, e := range env.Errors {
+		assert.Equal(t, 500, e.Code)
+		key, _ := json.Marshal(e.Loc)
+		msgs[string(key)] = e.Msg
+	}
+	assert.Equal(t, "expected string but got integer", msgs[`["response","status"]`])
+	assert.Equal(t, "expected array but got string", msgs[`["response","items"]`])
+}
+
+func TestResponseValidation_RejectWithDefaultErrorShape(t *testing.T) {
+	app := fiber.New()
+	oapi := New(app, Config{
+		DefaultErrorShape:        &UniErr{},
+		ResponseValidation:       ResponseValidationReject,
+		ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) {},
+	})
+	Get(oapi, "/orders", func(c fiber.Ctx, in struct{}) (rvOutput, error) {
+		return rvOutput{}, nil
+	}, OpenAPIOptions{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	require.Equal(t, 500, resp.StatusCode)
+
+	var body UniErr
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 500, body.Code)
+	assert.Equal(t, "response_validation_error", body.Type)
+	assert.Contains(t, body.Details, "status")
+}
+
+func TestResponseValidation_NullOptionalFieldsAccepted(t *testing.T) {
+	app := fiber.New()
+	called := false
+	oapi := New(app, Config{
+		ResponseValidation:       ResponseValidationReject,
+		ResponseViolationHandler: func(c fiber.Ctx, v ResponseViolation) { called = true },
+	})
+	Get(oapi, "/orders", func(c fiber.Ctx, in struct{}) (rvOutput, error) {
+		// Items and Note serialise as null.
+		return rvOutput{Status: "archived"}, nil
+	}, OpenAPIOptions{})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/orders", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.False(t, called)
+}
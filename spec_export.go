@@ -0,0 +1,249 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// SpecFormat selects the serialisation used by WriteSpec.
+type SpecFormat string
+
+const (
+	SpecFormatJSON SpecFormat = "json"
+	SpecFormatYAML SpecFormat = "yaml"
+)
+
+// specFormatFromPath infers the format from a file extension, defaulting to
+// JSON for anything that is not .yaml / .yml.
+func specFormatFromPath(path string) SpecFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return SpecFormatYAML
+	default:
+		return SpecFormatJSON
+	}
+}
+
+// MarshalSpec renders the generated spec in the requested format. JSON output
+// is indented and ends with a newline so checked-in files diff cleanly.
+func (o *OApiApp) MarshalSpec(format SpecFormat) ([]byte, error) {
+	switch format {
+	case SpecFormatJSON:
+		data, err := json.MarshalIndent(o.GenerateOpenAPISpec(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	case SpecFormatYAML:
+		spec, err := o.GenerateOpenAPISpecYAML()
+		if err != nil {
+			return nil, err
+		}
+		return []byte(spec), nil
+	default:
+		return nil, fmt.Errorf("unsupported spec format %q", format)
+	}
+}
+
+// WriteSpec writes the generated spec to path without starting the server.
+// An empty format is inferred from the file extension (.yaml / .yml → YAML,
+// anything else → JSON).
+func (o *OApiApp) WriteSpec(path string, format SpecFormat) error {
+	if format == "" {
+		format = specFormatFromPath(path)
+	}
+	data, err := o.MarshalSpec(format)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SpecLintIssue describes one problem found in the generated spec.
+type SpecLintIssue struct {
+	Location string // "GET /users/{id}", or a JSON pointer for spec-wide issues
+	Message  string
+}
+
+func (i SpecLintIssue) String() string {
+	return i.Location + ": " + i.Message
+}
+
+// SpecLintError is returned by ExportSpec when LintSpec reports issues.
+type SpecLintError struct {
+	Issues []SpecLintIssue
+}
+
+func (e *SpecLintError) Error() string {
+	lines := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		lines[i] = issue.String()
+	}
+	return fmt.Sprintf("spec has %d lint issue(s):\n  %s", len(e.Issues), strings.Join(lines, "\n  "))
+}
+
+// LintSpec checks the generated spec for problems that make it invalid or
+// misleading for consumers:
+//   - operationId used by more than one visible operation
+//   - path placeholder without a matching path parameter (typically a missing
+//     uri tag on the input struct), or a path parameter absent from the path
+//   - $ref pointing to a schema missing from components.schemas
+//
+// It returns nil when the spec is clean.
+func (o *OApiApp) LintSpec() []SpecLintIssue {
+	if scope := o.specScope(); scope != o {
+		return scope.LintSpec()
+	}
+	var issues []SpecLintIssue
+	spec := o.GenerateOpenAPISpec()
+
+	opIDs := map[string]string{}
+	for _, op := range o.operations {
+		if op.Options.Hidden {
+			continue
+		}
+		openAPIPath := convertFiberPathToOpenAPI(op.Path)
+		loc := op.Method + " " + openAPIPath
+
+		if id := op.Options.OperationID; id != "" {
+			if first, dup := opIDs[id]; dup {
+				issues = append(issues, SpecLintIssue{loc, fmt.Sprintf("operationId %q is already used by %s", id, first)})
+			} else {
+				opIDs[id] = loc
+			}
+		}
+
+		declared := map[string]bool{}
+		if pathItem, ok := spec["paths"].(map[string]interface{})[openAPIPath].(map[string]interface{}); ok {
+			if operation, ok := pathItem[strings.ToLower(op.Method)].(map[string]interface{}); ok {
+				params, _ := operation["parameters"].([]map[string]interface{})
+				for _, p := range params {
+					if p["in"] == "path" {
+						name, _ := p["name"].(string)
+						declared[name] = true
+					}
+				}
+			}
+		}
+		inPath := map[string]bool{}
+		for _, param := range extractFiberPathParams(openAPIPath) {
+			// Optional (:id?) and constrained (:id<int>) placeholders name "id".
+			name := pathParamName(param)
+			inPath[name] = true
+			if !declared[name] {
+				issues = append(issues, SpecLintIssue{loc, fmt.Sprintf("path placeholder {%s} has no path parameter (add a `uri:\"%s\"` field to the input)", name, name)})
+			}
+		}
+		names := make([]string, 0, len(declared))
+		for name := range declared {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !inPath[name] {
+				issues = append(issues, SpecLintIssue{loc, fmt.Sprintf("path parameter %q does not appear in the path", name)})
+			}
+		}
+	}
+
+	schemas, _ := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	collectDanglingRefs(spec, "#", schemas, &issues)
+	return issues
+}
+
+// collectDanglingRefs walks the spec and reports every components.schemas
+// $ref whose target does not exist.
+func collectDanglingRefs(node any, pointer string, schemas map[string]interface{}, issues *[]SpecLintIssue) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			if name, ok := strings.CutPrefix(ref, "#/components/schemas/"); ok {
+				if _, exists := schemas[name]; !exists {
+					*issues = append(*issues, SpecLintIssue{pointer, fmt.Sprintf("$ref %q points to a missing schema", ref)})
+				}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectDanglingRefs(v[k], pointer+"/"+strings.ReplaceAll(strings.ReplaceAll(k, "~", "~0"), "/", "~1"), schemas, issues)
+		}
+	case []map[string]interface{}:
+		for i, item := range v {
+			collectDanglingRefs(item, fmt.Sprintf("%s/%d", pointer, i), schemas, issues)
+		}
+	case []interface{}:
+		for i, item := range v {
+			collectDanglingRefs(item, fmt.Sprintf("%s/%d", pointer, i), schemas, issues)
+		}
+	}
+}
+
+// LoadRoutes builds an OApiApp on a fresh fiber.App and runs a route
+// registration function on it. No port is bound, which makes it suitable for
+// spec export and code generation. register must be one of:
+//
+//	func(*OApiApp)
+//	func(OApiRouter)
+//	func() *OApiApp   (config is ignored; the function builds its own app)
+func LoadRoutes(register any, config ...Config) (*OApiApp, error) {
+	switch fn := register.(type) {
+	case func(*OApiApp):
+		oapi := New(fiber.New(), config...)
+		fn(oapi)
+		return oapi, nil
+	case func(OApiRouter):
+		oapi := New(fiber.New(), config...)
+		fn(oapi)
+		return oapi, nil
+	case func() *OApiApp:
+		return fn(), nil
+	default:
+		return nil, fmt.Errorf("unsupported route registration function %T", register)
+	}
+}
+
+// ExportSpec loads the routes registered by register, lints the resulting
+// spec and writes it to path. Nothing is written when linting fails; the
+// returned error is then a *SpecLintError.
+func ExportSpec(register any, path string, format SpecFormat, config ...Config) error {
+	oapi, err := LoadRoutes(register, config...)
+	if err != nil {
+		return err
+	}
+	if issues := oapi.LintSpec(); len(issues) > 0 {
+		return &SpecLintError{Issues: issues}
+	}
+	return oapi.WriteSpec(path, format)
+}
+
+// ExportSpecMain is a ready-made main for a spec export command:
+//
+//	// cmd/openapi/main.go
+//	func main() { fiberoapi.ExportSpecMain(api.RegisterRoutes) }
+//
+// Run it with `go run ./cmd/openapi -out openapi.yaml`. It accepts -out
+// (default "openapi.json") and -format (json / yaml, inferred from -out when
+// omitted), prints lint issues to stderr and exits with status 1 on any lint
+// or write failure.
+func ExportSpecMain(register any, config ...Config) {
+	fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ExitOnError)
+	out := fs.String("out", "openapi.json", "destination file")
+	format := fs.String("format", "", "json or yaml (default: inferred from -out)")
+	_ = fs.Parse(os.Args[1:])
+
+	if err := ExportSpec(register, *out, SpecFormat(*format), config...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,165 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type exportItemInput struct {
+	ID string `uri:"id" validate:"required"`
+}
+
+type exportItem struct {
+	ID string `json:"id"`
+}
+
+func registerExportRoutes(router OApiRouter) {
+	Get(router, "/items/:id", func(c fiber.Ctx, in exportItemInput) (exportItem, error) {
+		return exportItem{ID: in.ID}, nil
+	}, OpenAPIOptions{OperationID: "getItem"})
+}
+
+func TestWriteSpec_FormatFromExtension(t *testing.T) {
+	oapi, err := LoadRoutes(registerExportRoutes)
+	require.NoError(t, err)
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "openapi.json")
+	require.NoError(t, oapi.WriteSpec(jsonPath, ""))
+	raw, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	var spec map[string]any
+	require.NoError(t, json.Unmarshal(raw, &spec))
+	assert.Contains(t, spec["paths"], "/items/{id}")
+
+	yamlPath := filepath.Join(dir, "openapi.yml")
+	require.NoError(t, oapi.WriteSpec(yamlPath, ""))
+	raw, err = os.ReadFile(yamlPath)
+	require.NoError(t, err)
+	spec = nil
+	require.NoError(t, yaml.Unmarshal(raw, &spec))
+	assert.Equal(t, "3.0.0", spec["openapi"])
+
+	// An explicit format wins over the extension.
+	txtPath := filepath.Join(dir, "spec.txt")
+	require.NoError(t, oapi.WriteSpec(txtPath, SpecFormatYAML))
+	raw, err = os.ReadFile(txtPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "openapi: 3.0.0")
+
+	assert.Error(t, oapi.WriteSpec(filepath.Join(dir, "x"), "xml"))
+}
+
+func TestLoadRoutes_Signatures(t *testing.T) {
+	for name, register := range map[string]any{
+		"app":    func(o *OApiApp) { registerExportRoutes(o) },
+		"router": registerExportRoutes,
+		"factory": func() *OApiApp {
+			o := New(fiber.New())
+			registerExportRoutes(o)
+			return o
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			oapi, err := LoadRoutes(register)
+			require.NoError(t, err)
+			assert.Len(t, oapi.GetOperations(), 1)
+		})
+	}
+
+	_, err := LoadRoutes(func(int) {})
+	assert.Error(t, err)
+}
+
+func TestLintSpec_Clean(t *testing.T) {
+	oapi, err := LoadRoutes(registerExportRoutes)
+	require.NoError(t, err)
+	assert.Empty(t, oapi.LintSpec())
+}
+
+func TestLintSpec_Issues(t *testing.T) {
+	oapi := New(fiber.New())
+	registerExportRoutes(oapi)
+	// Duplicate operationId.
+	Get(oapi, "/other/:id", func(c fiber.Ctx, in exportItemInput) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "getItem"})
+	// Placeholder without a uri field.
+	Get(oapi, "/orphans/:slug", func(c fiber.Ctx, in struct{}) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "getOrphan"})
+	// Manual path parameter missing from the path.
+	Get(oapi, "/ghosts", func(c fiber.Ctx, in struct{}) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "listGhosts", Parameters: []map[string]any{{"name": "ghost", "in": "path", "required": true}}})
+	// Hidden duplicates are ignored.
+	Get(oapi, "/hidden/:id", func(c fiber.Ctx, in exportItemInput) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "getItem", Hidden: true})
+
+	issues := oapi.LintSpec()
+	require.Len(t, issues, 3, "%v", issues)
+	assert.Equal(t, "GET /other/{id}", issues[0].Location)
+	assert.Contains(t, issues[0].Message, `operationId "getItem" is already used by GET /items/{id}`)
+	assert.Equal(t, "GET /orphans/{slug}", issues[1].Location)
+	assert.Contains(t, issues[1].Message, "{slug}")
+	assert.Equal(t, "GET /ghosts", issues[2].Location)
+	assert.Contains(t, issues[2].Message, `"ghost"`)
+}
+
+func TestLintSpec_OptionalAndConstrainedParams(t *testing.T) {
+	oapi := New(fiber.New())
+	Get(oapi, "/files/:name?", func(c fiber.Ctx, in struct {
+		Name string `uri:"name"`
+	}) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "getFile"})
+	Get(oapi, "/pages/:n<int>", func(c fiber.Ctx, in struct {
+		N int `uri:"n"`
+	}) (exportItem, error) {
+		return exportItem{}, nil
+	}, OpenAPIOptions{OperationID: "getPage"})
+
+	assert.Empty(t, oapi.LintSpec())
+}
+
+func TestLintSpec_DanglingRef(t *testing.T) {
+	oapi := New(fiber.New())
+	registerExportRoutes(oapi)
+	oapi.operations[0].Options.Parameters = []map[string]any{{
+		"name": "filter", "in": "query", "schema": map[string]any{"$ref": "#/components/schemas/Missing"},
+	}}
+
+	issues := oapi.LintSpec()
+	require.Len(t, issues, 1)
+	assert.Equal(t, "#/paths/~1items~1{id}/get/parameters/0/schema", issues[0].Location)
+	assert.Contains(t, issues[0].Message, "Missing")
+}
+
+func TestExportSpec(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "openapi.yaml")
+	require.NoError(t, ExportSpec(registerExportRoutes, out, ""))
+	_, err := os.Stat(out)
+	require.NoError(t, err)
+
+	bad := filepath.Join(dir, "bad.json")
+	err = ExportSpec(func(o *OApiApp) {
+		Get(o, "/orphans/:slug", func(c fiber.Ctx, in struct{}) (exportItem, error) {
+			return exportItem{}, nil
+		}, OpenAPIOptions{})
+	}, bad, "")
+	lintErr, ok := errors.AsType[*SpecLintError](err)
+	require.True(t, ok, "expected *SpecLintError, got %v", err)
+	assert.Len(t, lintErr.Issues, 1)
+	_, statErr := os.Stat(bad)
+	assert.True(t, os.IsNotExist(statErr), "nothing is written when lint fails")
+}
@@ -0,0 +1,206 @@
+package fiberoapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Constraint values carried by strict-mode parse_error entries.
+const (
+	strictUnknownField = "additionalProperties=false"
+	strictDuplicateKey = "duplicate_key"
+)
+
+// strictJSONError reports a body key rejected by strict JSON decoding. Loc is
+// the full location of the key, starting with "body".
+type strictJSONError struct {
+	Loc        []any
+	Key        string
+	Constraint string // strictUnknownField or strictDuplicateKey
+}
+
+func (e *strictJSONError) Error() string {
+	if e.Constraint == strictDuplicateKey {
+		return fmt.Sprintf("duplicate key '%s' in JSON body", e.Key)
+	}
+	return fmt.Sprintf("unknown field '%s' in JSON body", e.Key)
+}
+
+// strictJSONEnabled resolves the per-operation override against the config.
+func strictJSONEnabled(cfg Config, options *OpenAPIOptions) bool {
+	if options != nil && options.StrictJSON != nil {
+		return *options.StrictJSON
+	}
+	return cfg.StrictJSON
+}
+
+var bodyFieldsCache sync.Map // map[reflect.Type]map[string]reflect.Type
+
+// bodyFields lists the JSON keys a struct accepts in a strict request body:
+// exported fields that are neither json:"-", openapi:"-" nor bound to a
+// path / uri / query / header parameter, with embedded structs flattened the
+// way encoding/json decodes them. Keys are matched exactly, unlike
+// encoding/json's case-insensitive fallback.
+func bodyFields(t reflect.Type) map[string]reflect.Type {
+	if cached, ok := bodyFieldsCache.Load(t); ok {
+		return cached.(map[string]reflect.Type)
+	}
+	fields := map[string]reflect.Type{}
+	collectBodyFields(t, fields)
+	actual, _ := bodyFieldsCache.LoadOrStore(t, fields)
+	return actual.(map[string]reflect.Type)
+}
+
+func collectBodyFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" || field.Tag.Get("openapi") == "-" {
+			continue
+		}
+		if field.Tag.Get("path") != "" || field.Tag.Get("uri") != "" || field.Tag.Get("query") != "" || field.Tag.Get("header") != "" {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if field.Anonymous && name == "" && dereferenceType(field.Type).Kind() == reflect.Struct {
+			collectBodyFields(dereferenceType(field.Type), fields)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+}
+
+// checkStrictJSON walks body token by token and returns a *strictJSONError for
+// the first duplicate key, or the first key that t does not declare. Syntax
+// errors are ignored here so the regular decoder reports them.
+func checkStrictJSON(body []byte, t reflect.Type) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	err := walkStrictJSON(dec, t, []any{"body"})
+	if _, ok := err.(*strictJSONError); ok {
+		return err
+	}
+	return nil
+}
+
+// walkStrictJSON consumes one JSON value from dec. t is the Go type the value
+// decodes into, or nil when any content is acceptable (interface fields,
+// json.RawMessage, custom unmarshalers).
+func walkStrictJSON(dec *json.Decoder, t reflect.Type, loc []any) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	t = strictElemType(t)
+
+	switch tok {
+	case json.Delim('{'):
+		var fields map[string]reflect.Type
+		var mapElem reflect.Type
+		checkFields := false
+		if t != nil {
+			switch t.Kind() {
+			case reflect.Struct:
+				fields, checkFields = bodyFields(t), true
+			case reflect.Map:
+				mapElem = t.Elem()
+			}
+		}
+		seen := map[string]bool{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			keyLoc := appendLoc(loc, key)
+			if seen[key] {
+				return &strictJSONError{Loc: keyLoc, Key: key, Constraint: strictDuplicateKey}
+			}
+			seen[key] = true
+
+			var child reflect.Type
+			switch {
+			case checkFields:
+				ft, ok := fields[key]
+				if !ok {
+					return &strictJSONError{Loc: keyLoc, Key: key, Constraint: strictUnknownField}
+				}
+				child = ft
+			case mapElem != nil:
+				child = mapElem
+			}
+			if err := walkStrictJSON(dec, child, keyLoc); err != nil {
+				return err
+			}
+		}
+		_, err := dec.Token() // closing }
+		return err
+	case json.Delim('['):
+		var elem reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			elem = t.Elem()
+		}
+		for i := 0; dec.More(); i++ {
+			if err := walkStrictJSON(dec, elem, appendLoc(loc, i)); err != nil {
+				return err
+			}
+		}
+		_, err := dec.Token() // closing ]
+		return err
+	default:
+		return nil
+	}
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// strictElemType dereferences t and returns nil for types whose JSON content
+// is not constrained by their Go structure.
+func strictElemType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+	t = dereferenceType(t)
+	if t.Kind() == reflect.Interface {
+		return nil
+	}
+	return t
+}
+
+// markClosedSchemas sets additionalProperties: false on every struct schema
+// reachable from a strict operation's input type, so the spec documents that
+// unknown fields are rejected.
+func (o *OApiApp) markClosedSchemas(schemas map[string]interface{}) {
+	closed := map[string]reflect.Type{}
+	for _, op := range o.operations {
+		if op.Options.Hidden || op.InputType == nil || !strictJSONEnabled(o.config, &op.Options) {
+			continue
+		}
+		if op.Method != "POST" && op.Method != "PUT" && op.Method != "PATCH" {
+			continue
+		}
+		collectAllTypes(op.InputType, closed)
+	}
+	for name, t := range closed {
+		if dereferenceType(t).Kind() != reflect.Struct {
+			continue
+		}
+		if schema, ok := schemas[name].(map[string]interface{}); ok {
+			schema["additionalProperties"] = false
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package fiberoapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictAudit struct {
+	Source string `json:"source"`
+}
+
+type strictLine struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+type strictOrderInput struct {
+	strictAudit
+	ID       string            `uri:"id"`
+	Email    string            `json:"email" validate:"required"`
+	Lines    []strictLine      `json:"lines"`
+	Meta     map[string]any    `json:"meta"`
+	Extra    json.RawMessage   `json:"extra"`
+	Labels   map[string]string `json:"labels"`
+	Internal string            `json:"internal" openapi:"-"`
+}
+
+type strictOrderOutput struct {
+	OK bool `json:"ok"`
+}
+
+func createStrictOrder(c fiber.Ctx, in strictOrderInput) (strictOrderOutput, error) {
+	return strictOrderOutput{OK: true}, nil
+}
+
+func TestStrictJSON_AcceptsDeclaredFields(t *testing.T) {
+	oapi := newTestApp(Config{StrictJSON: true})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/orders/1", `{
+		"email": "a@b.c",
+		"source": "web",
+		"lines": [{"sku": "x", "qty": 1}],
+		"meta": {"anything": {"goes": [1, 2]}},
+		"extra": {"raw": true},
+		"labels": {"k": "v"}
+	}`)
+	assert.Equal(t, 200, resp.StatusCode, "%s", resp.body)
+}
+
+func TestStrictJSON_RejectsUnknownAndDuplicateKeys(t *testing.T) {
+	oapi := newTestApp(Config{StrictJSON: true})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+
+	cases := []struct {
+		name       string
+		body       string
+		loc        []any
+		constraint string
+	}{
+		{"top-level typo", `{"emial": "a@b.c"}`, []any{"body", "emial"}, "additionalProperties=false"},
+		{"nested in array", `{"email": "a", "lines": [{"sku": "x"}, {"skus": "y"}]}`, []any{"body", "lines", float64(1), "skus"}, "additionalProperties=false"},
+		{"case differs", `{"Email": "a"}`, []any{"body", "Email"}, "additionalProperties=false"},
+		{"path parameter in body", `{"email": "a", "ID": "2"}`, []any{"body", "ID"}, "additionalProperties=false"},
+		{"hidden field", `{"email": "a", "internal": "x"}`, []any{"body", "internal"}, "additionalProperties=false"},
+		{"duplicate key", `{"email": "a", "email": "b"}`, []any{"body", "email"}, "duplicate_key"},
+		{"duplicate in free-form map", `{"email": "a", "meta": {"k": 1, "k": 2}}`, []any{"body", "meta", "k"}, "duplicate_key"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := sendRequest(t, oapi, "POST", "/orders/1", tc.body)
+			require.Equal(t, 400, resp.StatusCode)
+			env := resp.envelope(t)
+			require.Len(t, env.Errors, 1)
+			e := env.Errors[0]
+			assert.Equal(t, "parse_error", e.Type)
+			assert.Equal(t, tc.loc, e.Loc)
+			assert.Equal(t, tc.constraint, e.Constraint)
+			assert.Equal(t, tc.loc[len(tc.loc)-1], e.Field)
+		})
+	}
+}
+
+func TestStrictJSON_SyntaxErrorsLeftToDecoder(t *testing.T) {
+	oapi := newTestApp(Config{StrictJSON: true})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/orders/1", `{"email": `)
+	require.Equal(t, 400, resp.StatusCode)
+	assert.NotEqual(t, "duplicate_key", resp.envelope(t).Errors[0].Constraint)
+}
+
+func TestStrictJSON_PerOperationOverride(t *testing.T) {
+	off, on := false, true
+
+	oapi := newTestApp(Config{StrictJSON: true})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{StrictJSON: &off})
+	resp := sendRequest(t, oapi, "POST", "/orders/1", `{"email": "a", "emial": "b"}`)
+	assert.Equal(t, 200, resp.StatusCode, "operation opted out")
+
+	oapi = newTestApp(Config{})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{StrictJSON: &on})
+	resp = sendRequest(t, oapi, "POST", "/orders/1", `{"email": "a", "emial": "b"}`)
+	assert.Equal(t, 400, resp.StatusCode, "operation opted in")
+
+	oapi = newTestApp(Config{})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+	resp = sendRequest(t, oapi, "POST", "/orders/1", `{"email": "a", "emial": "b"}`)
+	assert.Equal(t, 200, resp.StatusCode, "lenient by default")
+}
+
+func TestStrictJSON_DefaultErrorShape(t *testing.T) {
+	oapi := newTestApp(Config{StrictJSON: true, DefaultErrorShape: &UniErr{}})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+	resp := sendRequest(t, oapi, "POST", "/orders/1", `{"emial": "a"}`)
+	require.Equal(t, 400, resp.StatusCode)
+
+	var body UniErr
+	resp.decode(t, &body)
+	assert.Equal(t, "parse_error", body.Type)
+	assert.Equal(t, "unknown field 'emial' in JSON body", body.Message)
+	assert.Equal(t, "additionalProperties=false", body.Details)
+}
+
+func TestStrictJSON_SpecClosesRequestSchemas(t *testing.T) {
+	oapi := newTestApp(Config{StrictJSON: true})
+	Post(oapi, "/orders/:id", createStrictOrder, OpenAPIOptions{})
+	Get(oapi, "/orders/:id", func(c fiber.Ctx, in struct {
+		ID string `uri:"id"`
+	}) (strictOrderOutput, error) {
+		return strictOrderOutput{}, nil
+	}, OpenAPIOptions{})
+
+	schemas := oapi.GenerateOpenAPISpec()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Equal(t, false, schemas["strictOrderInput"].(map[string]interface{})["additionalProperties"])
+	assert.Equal(t, false, schemas["strictLine"].(map[string]interface{})["additionalProperties"], "nested types are closed too")
+	assert.NotContains(t, schemas["strictOrderOutput"], "additionalProperties", "response-only types stay open")
+}
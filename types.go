@@ -2,6 +2,7 @@ package fiberoapi
 
 import (
 	"reflect"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -17,7 +18,14 @@ type OApiApp struct {
 	f                 *fiber.App
 	operations        []OpenAPIOperation
 	config            Config
-	notFoundInstalled bool // true once UseNotFoundHandler has installed the catch-all
+	notFoundInstalled bool   // true once UseNotFoundHandler has installed the catch-all
+	specVersion       string // set on ForVersion views
+	errorMappings     []errorMapping
+	catalogs          map[string]MessageCatalog // validation messages by locale, see RegisterMessages
+	validation        *validationRules          // the app's validator and custom tags, see RegisterValidation
+	metrics           *metricsCollector         // nil unless Config.Metrics is set
+	rateLimits        RateLimitStore            // Config.RateLimitStore or an in-memory store
+	idempotency       IdempotencyStore          // Config.IdempotencyStore or an in-memory store
 }
 
 // Implement OApiRouter interface for OApiApp
@@ -39,6 +47,12 @@ func (o *OApiApp) Use(middleware fiber.Handler) {
 	o.f.Use(middleware)
 }
 
+// FiberApp returns the underlying fiber.App, e.g. to run requests through
+// its in-memory Test method.
+func (o *OApiApp) FiberApp() *fiber.App {
+	return o.f
+}
+
 // Listen starts the server on the given address
 func (o *OApiApp) Listen(addr string) error {
 	return o.f.Listen(addr)
@@ -93,6 +107,80 @@ type Config struct {
 	DefaultErrorShape any
 
 	IncludeInvalidValueInErrors bool // Include offending value in default error envelope (default: false — may leak secrets)
+
+	// ResponseValidation checks every handler output against its declared
+	// output type before it is sent: `validate` tags via struct validation,
+	// then the serialized JSON against the generated schema. Violations are
+	// reported to ResponseViolationHandler; ResponseValidationReject also
+	// replaces the response with a 500. Enable it in tests and staging only.
+	ResponseValidation       ResponseValidationMode
+	ResponseViolationHandler ResponseViolationHandler // Receives response violations (default: slog warning)
+
+	// Mock, when true, never calls the handlers: requests still go through
+	// parsing, validation and authorization, then get the operation's Example,
+	// a declared error selected with the X-Mock-Error header, or a payload
+	// synthesised from the output schema. See NewMockApp.
+	Mock bool
+
+	// StrictJSON rejects JSON request bodies containing a key the input type
+	// does not declare, or the same key twice in one object, with a 400
+	// parse_error whose loc points at the key. Request schemas of strict
+	// operations get additionalProperties: false in the spec. Overridable per
+	// operation with OpenAPIOptions.StrictJSON.
+	StrictJSON bool
+
+	// EnforceSunset answers 410 Gone, without running the handler, for every
+	// operation whose OpenAPIOptions.Sunset date has passed.
+	EnforceSunset bool
+
+	// Versioning, when set, registers each operation once per API version of
+	// its OpenAPIOptions.Versions range and serves one spec per version.
+	Versioning *Versioning
+
+	// RecoverPanics turns a panic raised while serving an operation into a
+	// 500 internal_error in the configured error shape, documented in the
+	// spec. The panic value and stack go to PanicHandler, never to the client.
+	RecoverPanics bool
+	PanicHandler  PanicHandler // Receives recovered panics (default: slog error with the stack)
+
+	// ProblemDetails renders every library error — validation, parse, auth,
+	// 404/405, body limits, rate limits, MapError and opaque handler errors —
+	// as an RFC 9457 ProblemDetails document served as
+	// application/problem+json, and documents it that way in the spec. It
+	// takes precedence over DefaultErrorShape. Error bodies a handler returns
+	// or declares in OpenAPIOptions.Errors are not library errors: they are
+	// still sent and documented as application/json in their own shape.
+	ProblemDetails bool
+	// ProblemTypeBaseURI prefixes the entry type to build the problem "type"
+	// URI (e.g. "https://errors.example.com/" + "validation_error"). When
+	// empty, "type" is "about:blank" and "title" is the HTTP status text.
+	ProblemTypeBaseURI string
+
+	// Localization renders validation messages in the locale of each request
+	// (Accept-Language by default), from the built-in "en" / "fr" catalogues
+	// and those registered with RegisterMessages.
+	Localization *Localization
+
+	// Hooks observes the request pipeline of every operation — parsing,
+	// validation, authorization, the handler and the response — with phase
+	// durations, for tracing, logging and metrics. See NopHooks.
+	Hooks Hooks
+
+	// Metrics, when set, counts the requests of every operation by status
+	// and error type and records their latency, labelled by operationId,
+	// method and route template, and serves them in the Prometheus text
+	// format at Metrics.Path.
+	Metrics *Metrics
+
+	// RateLimitStore holds the buckets of OpenAPIOptions.RateLimit (default:
+	// an in-memory store per app). Use a shared store to enforce limits
+	// across instances.
+	RateLimitStore RateLimitStore
+
+	// IdempotencyStore holds the responses of OpenAPIOptions.Idempotency
+	// (default: an in-memory store per app). Use a shared store to
+	// deduplicate retries across instances.
+	IdempotencyStore IdempotencyStore
 }
 
 // OpenAPIOptions represents options for OpenAPI operations
@@ -134,6 +222,62 @@ type OpenAPIOptions struct {
 	// generic parameter (which can be `error`, a concrete `*ErrorResponse`,
 	// or any other type) and the library emits it with the matching status.
 	Errors []any `json:"-"`
+
+	// Example is a sample success response. It is emitted as the example of
+	// the 200 response in the spec and served as-is in mock mode.
+	Example any `json:"-"`
+
+	// StrictJSON overrides Config.StrictJSON for this operation when non-nil.
+	StrictJSON *bool `json:"-"`
+
+	// Consumes lists the accepted request media types (e.g.
+	// "application/json", "application/x-www-form-urlencoded", "image/*").
+	// A body with any other Content-Type is rejected with 415. Empty accepts
+	// anything and documents application/json.
+	Consumes []string `json:"-"`
+
+	// MaxBodySize rejects request bodies larger than this many bytes with
+	// 413 and is published as x-max-body-size. Zero leaves only Fiber's
+	// global BodyLimit in place.
+	MaxBodySize int64 `json:"-"`
+
+	// Audience labels who the operation is meant for (e.g. "public",
+	// "internal", "partner"). It is published as x-audience.
+	Audience string `json:"-"`
+
+	// Deprecated marks the operation as deprecated in the spec and adds a
+	// Deprecation header to its responses. DeprecatedSince, Sunset and
+	// Successor imply it.
+	Deprecated bool `json:"-"`
+	// DeprecatedSince dates the deprecation (Deprecation: @<unix time>).
+	DeprecatedSince time.Time `json:"-"`
+	// Sunset is when the operation goes away: published as x-sunset, sent as
+	// the Sunset header, and enforced with 410 Gone by Config.EnforceSunset.
+	Sunset time.Time `json:"-"`
+	// Successor is the operationId of the replacement operation, advertised
+	// as x-successor and as a Link rel="successor-version" header.
+	Successor string `json:"-"`
+
+	// Versions restricts the operation to a range of Config.Versioning
+	// versions. The zero value registers it in every version.
+	Versions VersionRange `json:"-"`
+
+	// Middleware runs before the request is parsed, for this operation only,
+	// in declaration order. Call c.Next() to continue; returning without it
+	// short-circuits the operation. OperationFromCtx gives access to the
+	// operation's metadata.
+	Middleware []fiber.Handler `json:"-"`
+
+	// RateLimit caps the requests a client can make to the operation. Excess
+	// requests get a 429 with Retry-After; every response carries the
+	// RateLimit-* headers. It is documented as a 429 response and as
+	// x-rate-limit. See Config.RateLimitStore.
+	RateLimit *RateLimit `json:"-"`
+
+	// Idempotency stores the response to each Idempotency-Key and replays it
+	// to retries, for POST and PATCH operations. The header is documented as
+	// a parameter. See Config.IdempotencyStore.
+	Idempotency *Idempotency `json:"-"`
 }
 
 // OpenAPIOperation represents a registered operation
@@ -144,6 +288,7 @@ type OpenAPIOperation struct {
 	InputType  reflect.Type
 	OutputType reflect.Type
 	ErrorType  reflect.Type
+	Version    string // API version served by this operation; empty without Config.Versioning
 }
 
 type OpenAPIParameter struct {
@@ -198,8 +343,12 @@ type ValidationErrorEntry struct {
 }
 
 // ResponseContext carries metadata that helps a client correlate the response
-// with their tracing setup. ResponseID mirrors the incoming X-Request-Id header
-// when present, otherwise it is left empty.
+// with their tracing setup. ResponseID is the request ID assigned by
+// RequestIDMiddleware, or mirrors the incoming X-Request-Id header when the
+// middleware is not installed. TraceID and SpanID come from a valid
+// traceparent header, when the middleware is installed.
 type ResponseContext struct {
 	ResponseID string `json:"response_id,omitempty"`
+	TraceID    string `json:"trace_id,omitempty"`
+	SpanID     string `json:"span_id,omitempty"`
 }
@@ -0,0 +1,518 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TypeScriptConfig controls the output of GenerateTypeScript.
+type TypeScriptConfig struct {
+	ClientName string // Name of the generated fetch client class (default: "Client")
+}
+
+// GenerateTypeScript emits a self-contained TypeScript module: one interface
+// (or type alias) per component schema of the generated spec, plus a thin
+// fetch-based client with one method per visible operation. It is pure Go, so
+// it runs from a test or `go generate` without Node installed.
+//
+// Interfaces follow the JSON wire format: property names come from json tags,
+// embedded structs become `extends`, pointer and omitempty fields are optional,
+// and validate tags surface as enums (oneof) and doc comments (min, max,
+// email, ...).
+func (o *OApiApp) GenerateTypeScript(cfg TypeScriptConfig) ([]byte, error) {
+	if scope := o.specScope(); scope != o {
+		return scope.GenerateTypeScript(cfg)
+	}
+	if cfg.ClientName == "" {
+		cfg.ClientName = "Client"
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by fiberoapi-gen. DO NOT EDIT.\n/* eslint-disable */\n\n")
+
+	types := o.collectSpecTypes()
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeTSDeclaration(&b, name, types[name], o.validation)
+	}
+
+	writeTSClient(&b, cfg, o)
+	return []byte(b.String()), nil
+}
+
+// writeTSDeclaration renders one component schema.
+func writeTSDeclaration(b *strings.Builder, name string, t reflect.Type, rules *validationRules) {
+	t = dereferenceType(t)
+	if t.Kind() != reflect.Struct {
+		fmt.Fprintf(b, "export type %s = %s;\n\n", name, tsTypeBody(t, "", rules))
+		return
+	}
+
+	var extends []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if isTSExtends(field) {
+			extends = append(extends, getTypeName(field.Type))
+		}
+	}
+	fmt.Fprintf(b, "export interface %s", name)
+	if len(extends) > 0 {
+		fmt.Fprintf(b, " extends %s", strings.Join(extends, ", "))
+	}
+	b.WriteString(" {\n")
+	writeTSFields(b, t, "  ", rules)
+	b.WriteString("}\n\n")
+}
+
+// writeTSFields renders the properties of a struct, applying the same field
+// filtering as generateSchema so the interface matches the spec.
+func writeTSFields(b *strings.Builder, t reflect.Type, indent string, rules *validationRules) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if (!field.IsExported() && !field.Anonymous) || field.Tag.Get("openapi") == "-" {
+			continue
+		}
+		if field.Tag.Get("path") != "" || field.Tag.Get("query") != "" || field.Tag.Get("header") != "" {
+			continue
+		}
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		if field.Anonymous && jsonTag == "" && dereferenceType(field.Type).Kind() == reflect.Struct {
+			// encoding/json promotes the fields of embedded structs. Exported
+			// ones are components and rendered through extends; the fields
+			// of unexported ones are flattened in place.
+			if !isTSExtends(field) {
+				writeTSFields(b, dereferenceType(field.Type), indent, rules)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		validateTag := rules.expand(field.Tag.Get("validate"))
+		fieldSchema := generateFieldSchema(field.Type)
+		if validateTag != "" {
+			addValidationToSchema(fieldSchema, validateTag, rules)
+		}
+
+		if doc := tsDocComment(field, fieldSchema); doc != "" {
+			fmt.Fprintf(b, "%s/** %s */\n", indent, doc)
+		}
+		optional := ""
+		if isTSOptional(field, validateTag) {
+			optional = "?"
+		}
+		typ := tsTypeExpr(field.Type, indent, rules)
+		if enum, ok := fieldSchema["enum"].([]string); ok && fieldSchema["type"] != "array" {
+			typ = tsEnumUnion(enum, dereferenceType(field.Type))
+			if field.Type.Kind() == reflect.Ptr {
+				typ += " | null"
+			}
+		}
+		fmt.Fprintf(b, "%s%s%s: %s;\n", indent, tsPropertyName(jsonFieldName(field)), optional, typ)
+	}
+}
+
+// isTSExtends reports whether an embedded field is rendered as a base
+// interface: an exported, untagged, named struct that collectAllTypes
+// registers as its own component.
+func isTSExtends(field reflect.StructField) bool {
+	t := dereferenceType(field.Type)
+	return field.Anonymous && field.IsExported() && field.Tag.Get("json") == "" &&
+		t.Kind() == reflect.Struct && t.Name() != "" && !isTimeType(t)
+}
+
+// isTSOptional reports whether a property may be absent from the JSON object:
+// pointers and omitempty fields are optional unless validate marks them
+// required.
+func isTSOptional(field reflect.StructField, validateTag string) bool {
+	if strings.Contains(validateTag, "required") {
+		return false
+	}
+	if field.Type.Kind() == reflect.Ptr {
+		return true
+	}
+	parts := strings.Split(field.Tag.Get("json"), ",")
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			return true
+		}
+	}
+	return false
+}
+
+// tsDocComment builds the one-line JSDoc for a property from its description
+// tag and the constraints addValidationToSchema derived from its validate tag.
+func tsDocComment(field reflect.StructField, schema map[string]interface{}) string {
+	var parts []string
+	if desc := field.Tag.Get("description"); desc != "" {
+		parts = append(parts, desc)
+	} else if desc := field.Tag.Get("doc"); desc != "" {
+		parts = append(parts, desc)
+	}
+	for _, key := range []string{"format", "pattern", "minLength", "maxLength", "minimum", "maximum"} {
+		if v, ok := schema[key]; ok {
+			parts = append(parts, fmt.Sprintf("@%s %v", key, v))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// tsTypeExpr maps a Go type to a TypeScript type expression. Types that
+// collectAllTypes registers as components resolve to their component name;
+// everything else is inlined.
+func tsTypeExpr(t reflect.Type, indent string, rules *validationRules) string {
+	if t == nil {
+		return "unknown"
+	}
+	if t.Kind() == reflect.Ptr {
+		return tsTypeExpr(t.Elem(), indent, rules) + " | null"
+	}
+	if isTimeType(t) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Name() != "" {
+			return getTypeName(t)
+		}
+	case reflect.Map, reflect.Interface:
+		// Never registered as components, always inlined.
+	default:
+		if t.Name() != "" && shouldGenerateSchemaForType(t) {
+			return getTypeName(t)
+		}
+	}
+	return tsTypeBody(t, indent, rules)
+}
+
+// tsTypeBody renders the structure of t, ignoring its name. Used for inline
+// types and for the right-hand side of component type aliases.
+func tsTypeBody(t reflect.Type, indent string, rules *validationRules) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // encoding/json emits []byte as base64
+		}
+		elem := tsTypeExpr(t.Elem(), indent, rules)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + tsTypeExpr(t.Elem(), indent, rules) + ">"
+	case reflect.Struct:
+		var b strings.Builder
+		b.WriteString("{\n")
+		writeTSFields(&b, t, indent+"  ", rules)
+		b.WriteString(indent + "}")
+		return b.String()
+	default:
+		return "unknown"
+	}
+}
+
+// tsEnumUnion renders oneof values as a literal union, quoting them unless the
+// underlying Go type is numeric.
+func tsEnumUnion(values []string, t reflect.Type) string {
+	numeric := t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64
+	out := make([]string, len(values))
+	for i, v := range values {
+		if numeric {
+			out[i] = v
+		} else {
+			out[i] = strconv.Quote(v)
+		}
+	}
+	return strings.Join(out, " | ")
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsPropertyName(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsClientRuntime is the fixed part of the generated client. The verb is the
+// client class name.
+const tsClientRuntime = `export class ApiError extends Error {
+  constructor(readonly status: number, readonly body: unknown) {
+    super(` + "`unexpected status ${status}`" + `);
+  }
+}
+
+export interface ClientOptions {
+  /** Extra headers sent on every request, e.g. Authorization. */
+  headers?: Record<string, string>;
+  /** Custom fetch implementation (defaults to the global fetch). */
+  fetch?: typeof fetch;
+}
+
+export class %[1]s {
+  constructor(private readonly baseUrl: string, private readonly options: ClientOptions = {}) {}
+
+  private async request<T>(method: string, path: string, query: Record<string, unknown>, headers: Record<string, unknown>, body?: unknown): Promise<T> {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(query)) {
+      if (value === undefined || value === null) continue;
+      for (const v of Array.isArray(value) ? value : [value]) search.append(key, String(v));
+    }
+    const init: RequestInit = { method, headers: { Accept: "application/json", ...this.options.headers } };
+    const h = init.headers as Record<string, string>;
+    for (const [key, value] of Object.entries(headers)) {
+      if (value !== undefined && value !== null) h[key] = String(value);
+    }
+    if (body !== undefined) {
+      h["Content-Type"] = "application/json";
+      init.body = JSON.stringify(body);
+    }
+    const qs = search.toString();
+    const res = await (this.options.fetch ?? fetch)(this.baseUrl + path + (qs ? "?" + qs : ""), init);
+    const text = await res.text();
+    const data = text ? JSON.parse(text) : undefined;
+    if (!res.ok) throw new ApiError(res.status, data);
+    return data as T;
+  }
+`
+
+// writeTSClient renders the per-operation params interfaces, error unions and
+// the client class.
+func writeTSClient(b *strings.Builder, cfg TypeScriptConfig, o *OApiApp) {
+	var methods strings.Builder
+	seen := map[string]int{}
+	for _, op := range o.operations {
+		if op.Options.Hidden {
+			continue
+		}
+		name := goMethodName(op)
+		if n := seen[name]; n > 0 {
+			seen[name] = n + 1
+			name = name + strconv.Itoa(n+1)
+		} else {
+			seen[name] = 1
+		}
+		writeTSOperation(b, &methods, name, op, o.libraryErrorShape(), o.validation)
+	}
+	fmt.Fprintf(b, tsClientRuntime, cfg.ClientName)
+	b.WriteString(methods.String())
+	b.WriteString("}\n")
+}
+
+func writeTSOperation(decls, methods *strings.Builder, name string, op OpenAPIOperation, shape any, rules *validationRules) {
+	var pathParams, queryParams, headerParams []reflect.StructField
+	inType := dereferenceType(op.InputType)
+	hasInput := op.InputType != nil && !isEmptyStruct(op.InputType)
+	hasBody := false
+	if hasInput && inType.Kind() == reflect.Struct {
+		for i := 0; i < inType.NumField(); i++ {
+			f := inType.Field(i)
+			if !f.IsExported() || f.Tag.Get("openapi") == "-" {
+				continue
+			}
+			isParam := false
+			if f.Tag.Get("uri") != "" {
+				pathParams = append(pathParams, f)
+				isParam = true
+			}
+			if f.Tag.Get("query") != "" {
+				queryParams = append(queryParams, f)
+				isParam = true
+			}
+			if f.Tag.Get("header") != "" {
+				headerParams = append(headerParams, f)
+				isParam = true
+			}
+			if !isParam && f.Tag.Get("json") != "-" {
+				hasBody = true
+			}
+		}
+	} else if hasInput {
+		hasBody = true
+	}
+	if op.Method != http.MethodPost && op.Method != http.MethodPut && op.Method != http.MethodPatch {
+		hasBody = false
+	}
+
+	// Path placeholders not bound by a uri field still need a value.
+	bound := map[string]bool{}
+	for _, f := range pathParams {
+		bound[f.Tag.Get("uri")] = true
+	}
+	var unbound []string
+	for _, p := range extractFiberPathParams(op.Path) {
+		p = strings.TrimSuffix(p, "?")
+		if !bound[p] {
+			unbound = append(unbound, p)
+		}
+	}
+
+	var args []string
+	hasParams := len(pathParams)+len(queryParams)+len(headerParams)+len(unbound) > 0
+	if hasParams {
+		fmt.Fprintf(decls, "export interface %sParams {\n", name)
+		for _, f := range pathParams {
+			fmt.Fprintf(decls, "  %s: %s;\n", tsPropertyName(f.Tag.Get("uri")), tsTypeExpr(dereferenceType(f.Type), "  ", rules))
+		}
+		for _, p := range unbound {
+			fmt.Fprintf(decls, "  %s: string;\n", tsPropertyName(p))
+		}
+		for _, f := range queryParams {
+			writeTSParam(decls, f.Tag.Get("query"), f, rules)
+		}
+		for _, f := range headerParams {
+			writeTSParam(decls, f.Tag.Get("header"), f, rules)
+		}
+		decls.WriteString("}\n\n")
+		args = append(args, "params: "+name+"Params")
+	}
+	if hasBody {
+		args = append(args, "body: "+tsOperationType(op.InputType, rules))
+	}
+
+	// Error union: everything the Go client would decode for this operation.
+	errTypes := []string{}
+	addErr := func(t string) {
+		for _, e := range errTypes {
+			if e == t {
+				return
+			}
+		}
+		errTypes = append(errTypes, t)
+	}
+	for _, errInst := range op.Options.Errors {
+		if errInst != nil {
+			addErr(tsTypeExpr(dereferenceType(reflect.TypeOf(errInst)), "", rules))
+		}
+	}
+	if _, ok := shape.(ProblemDetails); ok {
+		addErr("ProblemDetails")
+	} else {
+		addErr("ErrorEnvelope")
+	}
+	if op.ErrorType != nil && !isEmptyStruct(op.ErrorType) && dereferenceType(op.ErrorType).Kind() == reflect.Struct {
+		addErr(tsTypeExpr(dereferenceType(op.ErrorType), "", rules))
+	} else if shape != nil {
+		addErr(tsTypeExpr(dereferenceType(reflect.TypeOf(shape)), "", rules))
+	}
+	fmt.Fprintf(decls, "/** Decoded ApiError.body variants for %s. */\nexport type %sError = %s;\n\n", name, name, strings.Join(errTypes, " | "))
+
+	// Method body.
+	pathExpr := op.Path
+	for _, f := range pathParams {
+		pathExpr = replaceTSPathParam(pathExpr, f.Tag.Get("uri"))
+	}
+	for _, p := range unbound {
+		pathExpr = replaceTSPathParam(pathExpr, p)
+	}
+	var query, headers []string
+	for _, f := range queryParams {
+		query = append(query, fmt.Sprintf("%s: params%s", tsPropertyName(f.Tag.Get("query")), tsAccessor(f.Tag.Get("query"))))
+	}
+	for _, f := range headerParams {
+		headers = append(headers, fmt.Sprintf("%s: params%s", tsPropertyName(f.Tag.Get("header")), tsAccessor(f.Tag.Get("header"))))
+	}
+	bodyArg := ""
+	if hasBody {
+		bodyArg = ", body"
+	}
+	outType := "void"
+	if op.Method != http.MethodHead {
+		outType = tsOperationType(op.OutputType, rules)
+	}
+
+	methodName := strings.ToLower(name[:1]) + name[1:]
+	methods.WriteString("\n  /**\n")
+	fmt.Fprintf(methods, "   * %s %s\n", op.Method, op.Path)
+	if op.Options.Summary != "" {
+		fmt.Fprintf(methods, "   *\n   * %s\n", op.Options.Summary)
+	}
+	fmt.Fprintf(methods, "   * @throws ApiError with a body of type %sError\n   */\n", name)
+	fmt.Fprintf(methods, "  %s(%s): Promise<%s> {\n", methodName, strings.Join(args, ", "), outType)
+	fmt.Fprintf(methods, "    return this.request<%s>(%q, `%s`, %s, %s%s);\n  }\n",
+		outType, op.Method, pathExpr, tsObjectLiteral(query), tsObjectLiteral(headers), bodyArg)
+}
+
+func writeTSParam(b *strings.Builder, name string, f reflect.StructField, rules *validationRules) {
+	optional := "?"
+	if isQueryFieldRequired(f) {
+		optional = ""
+	}
+	typ := tsTypeExpr(f.Type, "  ", rules)
+	if enum := oneofValues(rules.expand(f.Tag.Get("validate"))); enum != nil {
+		typ = tsEnumUnion(enum, dereferenceType(f.Type))
+	}
+	fmt.Fprintf(b, "  %s%s: %s;\n", tsPropertyName(name), optional, typ)
+}
+
+// oneofValues extracts the values of a oneof rule from a validate tag.
+func oneofValues(validateTag string) []string {
+	for _, rule := range strings.Split(validateTag, ",") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(rule), "oneof="); ok && v != "" {
+			return strings.Split(v, " ")
+		}
+	}
+	return nil
+}
+
+// tsOperationType names the request/response body type of an operation:
+// component name when the type is a component, inline type otherwise.
+func tsOperationType(t reflect.Type, rules *validationRules) string {
+	if t == nil {
+		return "unknown"
+	}
+	t = dereferenceType(t)
+	if t.Kind() == reflect.Struct && t.Name() != "" && !isTimeType(t) {
+		return getTypeName(t)
+	}
+	return tsTypeExpr(t, "  ", rules)
+}
+
+func tsObjectLiteral(entries []string) string {
+	if len(entries) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(entries, ", ") + " }"
+}
+
+func tsAccessor(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return "." + name
+	}
+	return "[" + strconv.Quote(name) + "]"
+}
+
+// replaceTSPathParam swaps a :name or {name} placeholder for an encoded
+// template-literal substitution.
+func replaceTSPathParam(path, name string) string {
+	sub := "${encodeURIComponent(String(params" + tsAccessor(name) + "))}"
+	segs := strings.Split(path, "/")
+	for i, seg := range segs {
+		if seg == ":"+name || seg == ":"+name+"?" || seg == "{"+name+"}" {
+			segs[i] = sub
+		}
+	}
+	return strings.Join(segs, "/")
+}
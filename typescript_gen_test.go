@@ -0,0 +1,109 @@
+package fiberoapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tsAudit struct {
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type tsAccount struct {
+	tsAudit
+	ID       string            `json:"id"`
+	Email    string            `json:"email" validate:"required,email" description:"Primary contact"`
+	Role     string            `json:"role" validate:"oneof=admin user"`
+	Nickname *string           `json:"nickname"`
+	Bio      string            `json:"bio,omitempty" validate:"max=500"`
+	Labels   map[string]string `json:"labels"`
+	Secret   string            `json:"-"`
+	Internal string            `json:"internal" openapi:"-"`
+}
+
+type tsListInput struct {
+	Org    string `uri:"org"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1"`
+	Sort   string `query:"sort" validate:"required,oneof=asc desc"`
+	Tenant string `header:"X-Tenant"`
+}
+
+type tsCreateInput struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=admin user"`
+}
+
+type tsNotFound struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newTSApp() *OApiApp {
+	oapi := New(fiber.New())
+	Get(oapi, "/orgs/:org/accounts", func(c fiber.Ctx, in tsListInput) ([]tsAccount, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "listAccounts", Summary: "List accounts"})
+	Post(oapi, "/accounts", func(c fiber.Ctx, in tsCreateInput) (*tsAccount, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "createAccount", Errors: []any{&tsNotFound{Code: 404}}})
+	Get(oapi, "/hidden", func(c fiber.Ctx, in struct{}) (fiber.Map, error) {
+		return nil, nil
+	}, OpenAPIOptions{OperationID: "hiddenOp", Hidden: true})
+	return oapi
+}
+
+func TestGenerateTypeScript_Interfaces(t *testing.T) {
+	src, err := newTSApp().GenerateTypeScript(TypeScriptConfig{})
+	require.NoError(t, err)
+	code := string(src)
+
+	// Fields of the unexported embedded struct are promoted, as encoding/json does.
+	assert.Contains(t, code, "export interface tsAccount {\n  /** @format date-time */\n  createdAt: string;\n  id: string;")
+	assert.Contains(t, code, "  /** Primary contact @format email */\n  email: string;")
+	assert.Contains(t, code, `  role: "admin" | "user";`)
+	assert.Contains(t, code, "  nickname?: string | null;")
+	assert.Contains(t, code, "  /** @maxLength 500 */\n  bio?: string;")
+	assert.Contains(t, code, "  labels: Record<string, string>;")
+	assert.NotContains(t, code, "Secret")
+	assert.NotContains(t, code, "internal")
+
+	// Library shapes are always present, like in components.schemas.
+	assert.Contains(t, code, "export interface ErrorEnvelope {")
+	assert.Contains(t, code, "  loc: unknown[];")
+}
+
+func TestGenerateTypeScript_Client(t *testing.T) {
+	src, err := newTSApp().GenerateTypeScript(TypeScriptConfig{ClientName: "AccountsClient"})
+	require.NoError(t, err)
+	code := string(src)
+
+	assert.Contains(t, code, "export class AccountsClient {")
+	assert.Contains(t, code, "export interface ListAccountsParams {\n  org: string;\n  limit?: number;\n  sort: \"asc\" | \"desc\";\n  \"X-Tenant\"?: string;\n}")
+	assert.Contains(t, code, "listAccounts(params: ListAccountsParams): Promise<tsAccount[]> {")
+	assert.Contains(t, code, "`/orgs/${encodeURIComponent(String(params.org))}/accounts`")
+	assert.Contains(t, code, `{ limit: params.limit, sort: params.sort }, { "X-Tenant": params["X-Tenant"] }`)
+
+	assert.Contains(t, code, "createAccount(body: tsCreateInput): Promise<tsAccount> {")
+	assert.Contains(t, code, "export type CreateAccountError = tsNotFound | ErrorEnvelope;")
+	assert.NotContains(t, code, "hiddenOp")
+
+	// Balanced braces are a cheap sanity check in the absence of tsc.
+	assert.Equal(t, strings.Count(code, "{"), strings.Count(code, "}"))
+}
+
+func TestGenerateTypeScript_ValidationRules(t *testing.T) {
+	oapi := newTestApp()
+	registerArticleRoute(t, oapi)
+	src, err := oapi.GenerateTypeScript(TypeScriptConfig{})
+	require.NoError(t, err)
+	code := string(src)
+
+	// Custom tags and aliases document the same constraints as the spec.
+	assert.Contains(t, code, "  /** @pattern "+slugPattern.String()+" */\n  slug: string;")
+	assert.Contains(t, code, "  /** @minLength 3 @maxLength 16 */\n  author: string;")
+}
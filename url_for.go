@@ -0,0 +1,277 @@
+package fiberoapi
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// URLFor builds the URL of the operation registered with operationID, so
+// Location headers and links follow the routes instead of hard-coding them.
+// params is nil, a map or an input struct (or a pointer to one):
+//   - a map fills the :param segments by name; the remaining entries are
+//     encoded as the query string;
+//   - a struct fills the :param segments from its uri-tagged fields and the
+//     query string from its query-tagged fields. Zero query fields are
+//     omitted (use a pointer to send a zero) and slices produce one query
+//     value per element.
+//
+// Only nil values count as absent: a zero id still fills its segment. A
+// missing value for a required path parameter is an error; optional
+// parameters (:name?) are dropped. With Config.Versioning, the operation is
+// looked up in the default version first, then in the others from the
+// newest; ForVersion(v).URLFor targets one version. The result is relative to
+// the server root:
+//
+//	loc, err := oapi.URLFor("getUser", map[string]any{"id": user.ID})
+//	c.Location(loc) // /api/v1/users/42
+func (o *OApiApp) URLFor(operationID string, params any) (string, error) {
+	op, err := o.operationByID(operationID)
+	if err != nil {
+		return "", err
+	}
+	return buildOperationURL(op.Path, params)
+}
+
+// URLFor is the typed variant of OApiApp.URLFor: input must be the input type
+// the operation was registered with.
+func URLFor[TInput any](app *OApiApp, operationID string, input TInput) (string, error) {
+	op, err := app.operationByID(operationID)
+	if err != nil {
+		return "", err
+	}
+	if want, got := op.InputType, reflect.TypeOf(input); want != got {
+		return "", fmt.Errorf("operation %q takes %v, not %v", operationID, want, got)
+	}
+	return buildOperationURL(op.Path, input)
+}
+
+// operationByID finds the operation registered with operationID in any
+// version, preferring the default version, then the newest.
+func (o *OApiApp) operationByID(operationID string) (*OpenAPIOperation, error) {
+	var found *OpenAPIOperation
+	rank := 0
+	for i := range o.operations {
+		op := &o.operations[i]
+		if op.Options.OperationID != operationID {
+			continue
+		}
+		if r := o.versionPreference(op.Version); found == nil || r > rank {
+			found, rank = op, r
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no operation registered with operationId %q", operationID)
+	}
+	return found, nil
+}
+
+// versionPreference ranks the versions an operation ID resolves to: the
+// default version first, then the newest.
+func (o *OApiApp) versionPreference(version string) int {
+	v := o.config.Versioning
+	if v == nil {
+		return 0
+	}
+	if version == v.defaultVersion() {
+		return len(v.Versions)
+	}
+	return slices.Index(v.Versions, version)
+}
+
+func buildOperationURL(path string, params any) (string, error) {
+	pathValues := map[string]string{}
+	query := url.Values{}
+
+	v := reflect.ValueOf(params)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			break
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Ptr:
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return "", fmt.Errorf("URL parameters map must have string keys, got %v", v.Type())
+		}
+		placeholders := extractFiberPathParams(path)
+		for i, p := range placeholders {
+			placeholders[i] = pathParamName(p)
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			values, err := urlValues(iter.Value())
+			if err != nil {
+				return "", fmt.Errorf("parameter %s: %w", key, err)
+			}
+			if contains(placeholders, key) {
+				if len(values) > 0 {
+					pathValues[key] = values[0]
+				}
+				continue
+			}
+			for _, s := range values {
+				query.Add(key, s)
+			}
+		}
+	case reflect.Struct:
+		if err := collectURLParams(v, pathValues, query); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("URL parameters must be a map or a struct, got %v", v.Type())
+	}
+
+	target, err := FillPath(path, pathValues)
+	if err != nil {
+		return "", err
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target, nil
+}
+
+func collectURLParams(v reflect.Value, pathValues map[string]string, query url.Values) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		uri, q := field.Tag.Get("uri"), field.Tag.Get("query")
+		if uri == "" && q == "" {
+			if field.Anonymous && dereferenceType(field.Type).Kind() == reflect.Struct {
+				for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+					fv = fv.Elem()
+				}
+				if fv.Kind() == reflect.Struct {
+					if err := collectURLParams(fv, pathValues, query); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if uri == "" && fv.IsZero() {
+			continue
+		}
+		values, err := urlValues(fv)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if uri != "" {
+			if len(values) > 0 {
+				pathValues[uri] = values[0]
+			}
+			continue
+		}
+		for _, s := range values {
+			query.Add(q, s)
+		}
+	}
+	return nil
+}
+
+// ParamValues renders a path, query or header parameter value as strings the
+// way the binder reads them back: nothing for nil, RFC 3339 for time.Time,
+// MarshalText for encoding.TextMarshaler and one string per element for
+// slices. Zero values are values: 0 renders "0".
+func ParamValues(v any) ([]string, error) {
+	return urlValues(reflect.ValueOf(v))
+}
+
+func urlValues(v reflect.Value) ([]string, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		out := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s, err := urlValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	s, err := urlValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func urlValue(v reflect.Value) (string, error) {
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case time.Time:
+			return x.Format(time.RFC3339Nano), nil
+		case encoding.TextMarshaler:
+			b, err := x.MarshalText()
+			return string(b), err
+		}
+	}
+	return fmt.Sprint(v.Interface()), nil
+}
+
+// pathParamName strips the optional marker and constraint from a Fiber
+// parameter: "id<int>?" -> "id".
+func pathParamName(param string) string {
+	param = strings.TrimSuffix(param, "?")
+	if i := strings.IndexByte(param, '<'); i >= 0 {
+		param = param[:i]
+	}
+	return param
+}
+
+// FillPath substitutes the :name, :name? and :name<constraint> segments of a
+// Fiber path with path-escaped values. Absent optional segments are dropped;
+// absent required ones are an error. fiberoapitest builds its requests with it.
+func FillPath(path string, values map[string]string) (string, error) {
+	segs := strings.Split(path, "/")
+	out := make([]string, 0, len(segs))
+	var missing []string
+	for _, seg := range segs {
+		if !strings.HasPrefix(seg, ":") {
+			out = append(out, seg)
+			continue
+		}
+		name := pathParamName(seg[1:])
+		val, ok := values[name]
+		switch {
+		case ok:
+			out = append(out, url.PathEscape(val))
+		case strings.HasSuffix(seg, "?"):
+			// Optional and absent: drop the segment.
+		default:
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("missing path parameters for %s: %s", path, strings.Join(missing, ", "))
+	}
+	result := strings.Join(out, "/")
+	if result == "" {
+		return "/", nil
+	}
+	return result, nil
+}
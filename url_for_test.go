@@ -0,0 +1,176 @@
+package fiberoapi
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type urlForPaging struct {
+	Page int `query:"page"`
+}
+
+type urlForInput struct {
+	urlForPaging
+	OrgID  string    `uri:"org"`
+	ID     int       `uri:"id"`
+	Tags   []string  `query:"tag"`
+	Since  time.Time `query:"since"`
+	Filter *string   `query:"filter"`
+	Name   string    `json:"name"`
+}
+
+type urlForOutput struct {
+	OK bool `json:"ok"`
+}
+
+func newURLForApp() *OApiApp {
+	oapi := New(fiber.New())
+	v1 := Group(oapi, "/api/v1")
+	Get(v1, "/orgs/:org/items/:id", func(c fiber.Ctx, in urlForInput) (urlForOutput, error) {
+		return urlForOutput{OK: true}, nil
+	}, OpenAPIOptions{OperationID: "getItem"})
+	Get(oapi, "/files/:name?", func(c fiber.Ctx, in struct{}) (urlForOutput, error) {
+		return urlForOutput{}, nil
+	}, OpenAPIOptions{OperationID: "listFiles"})
+	Get(oapi, "/health", func(c fiber.Ctx, in struct{}) (urlForOutput, error) {
+		return urlForOutput{}, nil
+	}, OpenAPIOptions{OperationID: "health"})
+	return oapi
+}
+
+func TestURLFor_Map(t *testing.T) {
+	oapi := newURLForApp()
+
+	got, err := oapi.URLFor("getItem", map[string]any{"org": "acme corp", "id": 42, "page": 2})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/orgs/acme%20corp/items/42?page=2", got)
+
+	got, err = oapi.URLFor("health", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/health", got)
+
+	got, err = oapi.URLFor("listFiles", map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, "/files", got, "absent optional parameters are dropped")
+
+	got, err = oapi.URLFor("listFiles", map[string]string{"name": "a.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "/files/a.txt", got)
+}
+
+func TestURLFor_Struct(t *testing.T) {
+	oapi := newURLForApp()
+	filter := "open"
+	in := urlForInput{
+		urlForPaging: urlForPaging{Page: 3},
+		OrgID:        "acme",
+		ID:           7,
+		Tags:         []string{"a", "b"},
+		Since:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Filter:       &filter,
+		Name:         "ignored",
+	}
+
+	got, err := oapi.URLFor("getItem", in)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/orgs/acme/items/7?filter=open&page=3&since=2024-01-02T03%3A04%3A05Z&tag=a&tag=b", got)
+
+	typed, err := URLFor(oapi, "getItem", in)
+	require.NoError(t, err)
+	assert.Equal(t, got, typed)
+
+	ptr, err := oapi.URLFor("getItem", &in)
+	require.NoError(t, err)
+	assert.Equal(t, got, ptr)
+}
+
+func TestURLFor_ZeroValues(t *testing.T) {
+	oapi := newURLForApp()
+
+	// A zero id is a value, not a missing parameter; zero query fields are
+	// left out.
+	got, err := oapi.URLFor("getItem", urlForInput{OrgID: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/orgs/acme/items/0", got)
+
+	got, err = oapi.URLFor("getItem", map[string]any{"org": "acme", "id": 0, "page": 0})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/orgs/acme/items/0?page=0", got)
+
+	zero := ""
+	got, err = oapi.URLFor("getItem", urlForInput{OrgID: "acme", Filter: &zero})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/orgs/acme/items/0?filter=", got)
+}
+
+func TestFillPath(t *testing.T) {
+	got, err := FillPath("/a/:id<int>/b/:opt?", map[string]string{"id": "7"})
+	require.NoError(t, err)
+	assert.Equal(t, "/a/7/b", got)
+
+	got, err = FillPath("/files/:name", map[string]string{"name": "x/y"})
+	require.NoError(t, err)
+	assert.Equal(t, "/files/x%2Fy", got)
+}
+
+func TestURLFor_Versions(t *testing.T) {
+	oapi := New(fiber.New(), Config{Versioning: &Versioning{Versions: []string{"1", "2", "3"}, Default: "2"}})
+	handler := func(c fiber.Ctx, in struct {
+		ID int `uri:"id"`
+	}) (urlForOutput, error) {
+		return urlForOutput{}, nil
+	}
+	Get(oapi, "/users/:id", handler, OpenAPIOptions{OperationID: "getUser"})
+	Get(oapi, "/accounts/:id", handler, OpenAPIOptions{OperationID: "getAccount", Versions: VersionRange{From: "3"}})
+	Get(oapi, "/legacy/:id", handler, OpenAPIOptions{OperationID: "getLegacy", Versions: VersionRange{To: "1"}})
+
+	for id, want := range map[string]string{
+		"getUser":    "/v2/users/1", // the default version first
+		"getAccount": "/v3/accounts/1",
+		"getLegacy":  "/v1/legacy/1",
+	} {
+		got, err := oapi.URLFor(id, map[string]any{"id": 1})
+		require.NoError(t, err, id)
+		assert.Equal(t, want, got)
+	}
+
+	got, err := oapi.ForVersion("3").URLFor("getUser", map[string]any{"id": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "/v3/users/1", got)
+	_, err = oapi.ForVersion("3").URLFor("getLegacy", map[string]any{"id": 1})
+	assert.Error(t, err)
+}
+
+func TestURLFor_Errors(t *testing.T) {
+	oapi := newURLForApp()
+
+	_, err := oapi.URLFor("nope", nil)
+	assert.EqualError(t, err, `no operation registered with operationId "nope"`)
+
+	_, err = oapi.URLFor("getItem", map[string]any{"id": 1})
+	assert.EqualError(t, err, "missing path parameters for /api/v1/orgs/:org/items/:id: org")
+
+	_, err = oapi.URLFor("getItem", map[string]any{"id": 1, "org": (*string)(nil)})
+	assert.EqualError(t, err, "missing path parameters for /api/v1/orgs/:org/items/:id: org")
+
+	_, err = oapi.URLFor("getItem", 42)
+	assert.Error(t, err)
+
+	_, err = URLFor(oapi, "getItem", struct{ ID int }{ID: 1})
+	assert.ErrorContains(t, err, `operation "getItem" takes fiberoapi.urlForInput`)
+}
+
+func TestURLFor_RoundTrip(t *testing.T) {
+	oapi := newURLForApp()
+	target, err := URLFor(oapi, "getItem", urlForInput{OrgID: "acme", ID: 7, Tags: []string{"x"}})
+	require.NoError(t, err)
+
+	resp, err := oapi.FiberApp().Test(httptest.NewRequest("GET", target, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
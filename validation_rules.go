@@ -0,0 +1,125 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationRule describes a custom `validate` tag registered with
+// RegisterValidation: how it is enforced, explained and documented.
+type ValidationRule struct {
+	// Func reports whether the field satisfies the rule.
+	Func validator.Func
+	// CallEvenIfNull also runs Func on nil pointers, slices and maps.
+	CallEvenIfNull bool
+	// Messages holds the error message template per locale ("en", "fr", ...),
+	// with the placeholders of a MessageCatalog. Locales without one use the
+	// catalogue's "*" fallback.
+	Messages map[string]string
+	// Schema returns the JSON Schema keywords documenting the rule for its
+	// tag parameter (e.g. a pattern), merged into the field's schema.
+	Schema func(param string) map[string]interface{}
+}
+
+// validationRules is the validator of one OApiApp together with what the spec
+// needs to document its custom tags.
+type validationRules struct {
+	validate *validator.Validate
+	schemas  map[string]func(param string) map[string]interface{}
+	aliases  map[string]string
+}
+
+func newValidationRules() *validationRules {
+	return &validationRules{
+		validate: validator.New(),
+		schemas:  map[string]func(string) map[string]interface{}{},
+		aliases:  map[string]string{},
+	}
+}
+
+// Validator returns the app's validator, for registrations the helpers below
+// do not cover (custom type funcs, tag name funcs, ...). Each OApiApp owns its
+// own instance.
+func (o *OApiApp) Validator() *validator.Validate {
+	return o.validation.validate
+}
+
+// RegisterValidation adds a custom `validate` tag to the app:
+//
+//	oapi.RegisterValidation("slug", fiberoapi.ValidationRule{
+//		Func: func(fl validator.FieldLevel) bool { return slugRe.MatchString(fl.Field().String()) },
+//		Messages: map[string]string{
+//			"en": "field '{field}' must be a lowercase slug",
+//			"fr": "le champ '{field}' doit être un slug en minuscules",
+//		},
+//		Schema: func(string) map[string]interface{} {
+//			return map[string]interface{}{"pattern": slugRe.String()}
+//		},
+//	})
+//
+// Call it before registering the operations that use the tag.
+func (o *OApiApp) RegisterValidation(tag string, rule ValidationRule) error {
+	if rule.Func == nil {
+		return fmt.Errorf("fiberoapi: validation %q has no Func", tag)
+	}
+	if err := o.validation.validate.RegisterValidation(tag, rule.Func, rule.CallEvenIfNull); err != nil {
+		return err
+	}
+	if rule.Schema != nil {
+		o.validation.schemas[tag] = rule.Schema
+	}
+	for locale, template := range rule.Messages {
+		o.RegisterMessages(locale, MessageCatalog{tag: template})
+	}
+	return nil
+}
+
+// RegisterAlias maps a tag to a combination of existing ones, e.g.
+// RegisterAlias("username", "min=3,max=32,alphanum"). Error entries report
+// the tag of the combination that failed ("min=3"), and the spec documents
+// the alias with the schema of the tags it stands for.
+func (o *OApiApp) RegisterAlias(alias, tags string) {
+	o.validation.validate.RegisterAlias(alias, tags)
+	o.validation.aliases[alias] = tags
+}
+
+// RegisterStructValidation adds a struct-level rule run for each of the given
+// types (zero values or pointers to them). Report failures with
+// sl.ReportError; the tag passed there selects the message, as for field
+// tags.
+func (o *OApiApp) RegisterStructValidation(fn validator.StructLevelFunc, types ...any) {
+	o.validation.validate.RegisterStructValidation(fn, types...)
+}
+
+// expand replaces registered aliases in a validate tag by the tags they
+// stand for.
+func (r *validationRules) expand(validateTag string) string {
+	if r == nil || len(r.aliases) == 0 || validateTag == "" {
+		return validateTag
+	}
+	rules := strings.Split(validateTag, ",")
+	for i, rule := range rules {
+		if tags, ok := r.aliases[strings.TrimSpace(rule)]; ok {
+			rules[i] = tags
+		}
+	}
+	return strings.Join(rules, ",")
+}
+
+// addCustomSchema merges the schema keywords of a registered tag, reporting
+// whether the rule was one.
+func (r *validationRules) addCustomSchema(schema map[string]interface{}, rule string) bool {
+	if r == nil {
+		return false
+	}
+	name, param, _ := strings.Cut(rule, "=")
+	fn, ok := r.schemas[name]
+	if !ok {
+		return false
+	}
+	maps.Copy(schema, fn(param))
+	return true
+}
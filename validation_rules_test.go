@@ -0,0 +1,145 @@
+package fiberoapi
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type articleInput struct {
+	Slug   string `json:"slug" validate:"required,slug"`
+	Author string `json:"author" validate:"omitempty,username"`
+}
+
+type signupInput struct {
+	Password string `json:"password" validate:"required"`
+	Confirm  string `json:"confirm" validate:"required"`
+}
+
+func slugRule() ValidationRule {
+	return ValidationRule{
+		Func: func(fl validator.FieldLevel) bool { return slugPattern.MatchString(fl.Field().String()) },
+		Messages: map[string]string{
+			"en": "field '{field}' must be a lowercase slug",
+			"fr": "le champ '{field}' doit être un slug en minuscules",
+		},
+		Schema: func(string) map[string]interface{} {
+			return map[string]interface{}{"pattern": slugPattern.String()}
+		},
+	}
+}
+
+// registerArticleRoute registers the slug rule and the username alias on oapi,
+// then a route validating articleInput.
+func registerArticleRoute(t *testing.T, oapi *OApiApp) {
+	t.Helper()
+	require.NoError(t, oapi.RegisterValidation("slug", slugRule()))
+	oapi.RegisterAlias("username", "min=3,max=16,alphanum")
+	Post(oapi, "/articles", func(c fiber.Ctx, in articleInput) (uniOutput, error) {
+		return uniOutput{Message: in.Slug}, nil
+	}, OpenAPIOptions{OperationID: "createArticle"})
+}
+
+func TestRegisterValidation_EnforcedAndExplained(t *testing.T) {
+	oapi := newTestApp(Config{Localization: &Localization{}})
+	registerArticleRoute(t, oapi)
+
+	resp := sendRequest(t, oapi, "POST", "/articles", `{"slug":"hello-world"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp = sendRequest(t, oapi, "POST", "/articles", `{"slug":"Hello World"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	e := env.Errors[0]
+	assert.Equal(t, []any{"body", "slug"}, e.Loc)
+	assert.Equal(t, "slug", e.Constraint)
+	assert.Equal(t, "field 'slug' must be a lowercase slug", e.Msg)
+
+	resp = sendRequest(t, oapi, "POST", "/articles", `{"slug":"Hello World"}`, "Accept-Language", "fr")
+	assert.Equal(t, "le champ 'slug' doit être un slug en minuscules", resp.envelope(t).Errors[0].Msg)
+}
+
+func TestRegisterAlias_ReportsFailingTag(t *testing.T) {
+	oapi := newTestApp()
+	registerArticleRoute(t, oapi)
+	resp := sendRequest(t, oapi, "POST", "/articles", `{"slug":"ok","author":"ab"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "min=3", env.Errors[0].Constraint)
+	assert.Equal(t, "field 'author' must be at least 3", env.Errors[0].Msg)
+}
+
+func TestValidationRules_Spec(t *testing.T) {
+	oapi := newTestApp()
+	registerArticleRoute(t, oapi)
+	schemas := oapi.GenerateOpenAPISpec()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	props := schemas["articleInput"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	assert.Equal(t, slugPattern.String(), props["slug"].(map[string]interface{})["pattern"])
+	author := props["author"].(map[string]interface{})
+	assert.Equal(t, 3, author["minLength"])
+	assert.Equal(t, 16, author["maxLength"])
+}
+
+func TestRegisterStructValidation(t *testing.T) {
+	oapi := newTestApp()
+	oapi.RegisterStructValidation(func(sl validator.StructLevel) {
+		in := sl.Current().Interface().(signupInput)
+		if in.Password != in.Confirm {
+			sl.ReportError(in.Confirm, "confirm", "Confirm", "matches", "password")
+		}
+	}, signupInput{})
+	oapi.RegisterMessages("en", MessageCatalog{"matches": "field '{field}' must match '{param}'"})
+	Post(oapi, "/signup", func(c fiber.Ctx, in signupInput) (uniOutput, error) {
+		return uniOutput{Message: "ok"}, nil
+	}, OpenAPIOptions{})
+
+	resp := sendRequest(t, oapi, "POST", "/signup", `{"password":"s3cret","confirm":"s3cret"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp = sendRequest(t, oapi, "POST", "/signup", `{"password":"s3cret","confirm":"other"}`)
+	require.Equal(t, 422, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	e := env.Errors[0]
+	assert.Equal(t, []any{"body", "confirm"}, e.Loc)
+	assert.Equal(t, "matches=password", e.Constraint)
+	assert.Equal(t, "field 'confirm' must match 'password'", e.Msg)
+}
+
+func TestValidationRules_PerApp(t *testing.T) {
+	strict := newTestApp()
+	registerArticleRoute(t, strict)
+
+	lenient := newTestApp()
+	require.NoError(t, lenient.RegisterValidation("slug", ValidationRule{
+		Func: func(fl validator.FieldLevel) bool { return fl.Field().String() != "" },
+	}))
+	lenient.RegisterAlias("username", "alphanum")
+	Post(lenient, "/articles", func(c fiber.Ctx, in articleInput) (uniOutput, error) {
+		return uniOutput{Message: in.Slug}, nil
+	}, OpenAPIOptions{})
+
+	resp := sendRequest(t, strict, "POST", "/articles", `{"slug":"Not A Slug"}`)
+	assert.Equal(t, 422, resp.StatusCode)
+	resp = sendRequest(t, lenient, "POST", "/articles", `{"slug":"Not A Slug"}`)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.NotSame(t, strict.Validator(), lenient.Validator())
+
+	schemas := lenient.GenerateOpenAPISpec()["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	slug := schemas["articleInput"].(map[string]interface{})["properties"].(map[string]interface{})["slug"].(map[string]interface{})
+	assert.NotContains(t, slug, "pattern")
+}
+
+func TestRegisterValidation_RequiresFunc(t *testing.T) {
+	oapi := newTestApp()
+	assert.Error(t, oapi.RegisterValidation("slug", ValidationRule{}))
+}
@@ -0,0 +1,275 @@
+package fiberoapi
+
+import (
+	"fmt"
+	"mime"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// VersionStrategy selects how a request names the API version it targets.
+type VersionStrategy int
+
+const (
+	// VersionByPath prefixes every versioned route with PathPrefix + version
+	// ("/v1/users", "/v2/users"). Unknown versions are plain 404s.
+	VersionByPath VersionStrategy = iota
+	// VersionByHeader reads the version from a request header
+	// ("Accept-Version: 2"), falling back to the default version.
+	VersionByHeader
+	// VersionByMediaType reads the version from a parameter of the Accept
+	// header ("Accept: application/json; version=2"), falling back to the
+	// default version.
+	VersionByMediaType
+)
+
+// errTypeUnsupportedVersion is the entry type of the 406 served when a request
+// names a version that is not in Versioning.Versions.
+const errTypeUnsupportedVersion = "unsupported_version"
+
+// versionLocalsKey is the fiber.Ctx locals key holding the resolved version.
+const versionLocalsKey = "fiberoapi.version"
+
+// versionSkipLocalsKey holds the *OpenAPIOperation whose route is being
+// skipped because it serves another version than the requested one.
+const versionSkipLocalsKey = "fiberoapi.version.skip"
+
+// Versioning enables API versioning (Config.Versioning). Operations are
+// registered for a range of versions with OpenAPIOptions.Versions, and each
+// version gets its own spec.
+type Versioning struct {
+	// Versions lists the supported versions, oldest first. Ranges and the
+	// default version refer to this order.
+	Versions []string
+	// Strategy selects how requests name a version (default: VersionByPath).
+	Strategy VersionStrategy
+	// PathPrefix precedes the version in paths (default: "/v"). It prefixes the
+	// routes under VersionByPath and the per-version docs routes under every
+	// strategy: /v1/openapi.json, /v1/docs...
+	PathPrefix string
+	// Header carries the version under VersionByHeader (default: "Accept-Version").
+	Header string
+	// MediaTypeParam is the Accept parameter carrying the version under
+	// VersionByMediaType (default: "version").
+	MediaTypeParam string
+	// Default is the version served when a request names none, and the one
+	// documented by GenerateOpenAPISpec (default: the latest).
+	Default string
+}
+
+// VersionRange restricts an operation to a contiguous range of
+// Versioning.Versions. Empty bounds are open: the zero value means every
+// version.
+type VersionRange struct {
+	From string // first version serving the operation
+	To   string // last version serving the operation
+}
+
+func (v *Versioning) pathPrefix() string {
+	if v.PathPrefix == "" {
+		return "/v"
+	}
+	return v.PathPrefix
+}
+
+func (v *Versioning) header() string {
+	if v.Header == "" {
+		return "Accept-Version"
+	}
+	return v.Header
+}
+
+func (v *Versioning) mediaTypeParam() string {
+	if v.MediaTypeParam == "" {
+		return "version"
+	}
+	return v.MediaTypeParam
+}
+
+func (v *Versioning) defaultVersion() string {
+	if v.Default != "" {
+		return v.Default
+	}
+	if len(v.Versions) == 0 {
+		return ""
+	}
+	return v.Versions[len(v.Versions)-1]
+}
+
+// VersionFromCtx returns the API version the request was routed to, or "" when
+// versioning is disabled.
+func VersionFromCtx(c fiber.Ctx) string {
+	v, _ := c.Locals(versionLocalsKey).(string)
+	return v
+}
+
+type versionRoute struct {
+	path    string
+	version string
+}
+
+// versionRoutes expands an operation into one route per version of its range.
+// Under VersionByPath each route gets its version prefix; otherwise all share
+// the same path and the version is selected at request time.
+func (o *OApiApp) versionRoutes(fullPath string, r VersionRange) []versionRoute {
+	v := o.config.Versioning
+	if v == nil {
+		if r != (VersionRange{}) {
+			panic(fmt.Sprintf("OpenAPIOptions.Versions set on %s but Config.Versioning is not configured", fullPath))
+		}
+		return []versionRoute{{path: fullPath}}
+	}
+	from, to := 0, len(v.Versions)-1
+	if r.From != "" {
+		if from = slices.Index(v.Versions, r.From); from < 0 {
+			panic(fmt.Sprintf("unknown API version %q for %s; supported: %s", r.From, fullPath, strings.Join(v.Versions, ", ")))
+		}
+	}
+	if r.To != "" {
+		if to = slices.Index(v.Versions, r.To); to < 0 {
+			panic(fmt.Sprintf("unknown API version %q for %s; supported: %s", r.To, fullPath, strings.Join(v.Versions, ", ")))
+		}
+	}
+	var routes []versionRoute
+	for _, version := range v.Versions[from : to+1] {
+		path := fullPath
+		if v.Strategy == VersionByPath {
+			path = v.pathPrefix() + version + fullPath
+		}
+		routes = append(routes, versionRoute{path: path, version: version})
+	}
+	return routes
+}
+
+// requestedVersion returns the version named by the request under the header
+// and media type strategies, or the default version when it names none.
+func (o *OApiApp) requestedVersion(c fiber.Ctx) string {
+	v := o.config.Versioning
+	switch v.Strategy {
+	case VersionByHeader:
+		if requested := strings.TrimSpace(c.Get(v.header())); requested != "" {
+			return requested
+		}
+	case VersionByMediaType:
+		for _, accepted := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+			if _, params, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil {
+				if requested := params[v.mediaTypeParam()]; requested != "" {
+					return requested
+				}
+			}
+		}
+	}
+	return v.defaultVersion()
+}
+
+// selectVersion runs before an operation's middleware. Under VersionByPath the
+// route already fixes the version; otherwise a request for another version
+// moves on to the next route registered on the same path, and a request for an
+// unsupported version is answered 406.
+func (o *OApiApp) selectVersion(c fiber.Ctx, op *OpenAPIOperation) (handled bool, err error) {
+	v := o.config.Versioning
+	if v.Strategy == VersionByPath {
+		c.Locals(versionLocalsKey, op.Version)
+		return false, nil
+	}
+	requested := o.requestedVersion(c)
+	if !slices.Contains(v.Versions, requested) {
+		return true, o.serveUnsupportedVersion(c, requested)
+	}
+	c.Locals(versionLocalsKey, requested)
+	if requested != op.Version {
+		c.Locals(versionSkipLocalsKey, op)
+		return true, c.Next()
+	}
+	c.Locals(versionSkipLocalsKey, nil)
+	return false, nil
+}
+
+// notFoundVersion returns the version a request that matched no operation was
+// aimed at, so the 404 / 405 handler only considers that version's routes.
+func (o *OApiApp) notFoundVersion(c fiber.Ctx) string {
+	if version := VersionFromCtx(c); version != "" {
+		return version
+	}
+	if v := o.config.Versioning; v != nil && v.Strategy != VersionByPath {
+		return o.requestedVersion(c)
+	}
+	return ""
+}
+
+// skipOtherVersion wraps a handler of op's route so that it passes through
+// while selectVersion is skipping that route.
+func skipOtherVersion(op *OpenAPIOperation, h fiber.Handler) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		if skipped, _ := c.Locals(versionSkipLocalsKey).(*OpenAPIOperation); skipped == op {
+			return c.Next()
+		}
+		return h(c)
+	}
+}
+
+func (o *OApiApp) unsupportedVersionEntry(requested string) ValidationErrorEntry {
+	v := o.config.Versioning
+	loc := []any{"header", v.header()}
+	if v.Strategy == VersionByMediaType {
+		loc = []any{"header", fiber.HeaderAccept}
+	}
+	return ValidationErrorEntry{
+		Type:       errTypeUnsupportedVersion,
+		Code:       fiber.StatusNotAcceptable,
+		Loc:        loc,
+		Field:      loc[1].(string),
+		Msg:        fmt.Sprintf("API version %q is not supported; supported: %s", requested, strings.Join(v.Versions, ", ")),
+		Constraint: "versions=" + strings.Join(v.Versions, " "),
+	}
+}
+
+func (o *OApiApp) serveUnsupportedVersion(c fiber.Ctx, requested string) error {
+	entry := o.unsupportedVersionEntry(requested)
+	return o.sendError(c, fiber.StatusNotAcceptable, errorCategory{
+		Code:    entry.Code,
+		Type:    entry.Type,
+		Message: entry.Msg,
+		Details: entry.Constraint,
+	}, []ValidationErrorEntry{entry})
+}
+
+// ForVersion returns a read-only view of the app restricted to the operations
+// of one API version. Its spec, generated clients, lint and URLFor only see
+// that version:
+//
+//	v1 := oapi.ForVersion("1")
+//	_ = v1.WriteSpec("openapi.v1.yaml", fiberoapi.SpecFormatYAML)
+//
+// Routes must not be registered on the view. Without Config.Versioning the app
+// itself is returned.
+func (o *OApiApp) ForVersion(version string) *OApiApp {
+	if o.config.Versioning == nil {
+		return o
+	}
+	view := *o
+	view.specVersion = version
+	view.operations = nil
+	for _, op := range o.operations {
+		if op.Version == version {
+			view.operations = append(view.operations, op)
+		}
+	}
+	return &view
+}
+
+// specScope is the app whose operations the spec and the generators describe:
+// the default version's view when versioning is enabled.
+func (o *OApiApp) specScope() *OApiApp {
+	if o.config.Versioning == nil || o.specVersion != "" {
+		return o
+	}
+	return o.ForVersion(o.config.Versioning.defaultVersion())
+}
+
+// versionedDocsPath returns path under the docs prefix of version.
+func (v *Versioning) versionedDocsPath(path, version string) string {
+	return v.pathPrefix() + version + path
+}
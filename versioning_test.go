@@ -0,0 +1,194 @@
+package fiberoapi
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type versionedUserInput struct {
+	ID string `uri:"id"`
+}
+
+type userV1 struct {
+	Name string `json:"name"`
+}
+
+type versionInfo struct {
+	Version string `json:"version"`
+}
+
+type userV2 struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+// registerVersionedRoutes declares getUser in two shapes (up to 1, from 2 on),
+// health in every version and deleteUser in versions 1 and 2.
+func registerVersionedRoutes(oapi *OApiApp) {
+	Get(oapi, "/users/:id", func(c fiber.Ctx, in versionedUserInput) (userV1, error) {
+		return userV1{Name: "Ada Lovelace " + VersionFromCtx(c)}, nil
+	}, OpenAPIOptions{OperationID: "getUser", Versions: VersionRange{To: "1"}})
+	Get(oapi, "/users/:id", func(c fiber.Ctx, in versionedUserInput) (userV2, error) {
+		return userV2{FirstName: "Ada", LastName: VersionFromCtx(c)}, nil
+	}, OpenAPIOptions{OperationID: "getUser", Versions: VersionRange{From: "2"}})
+	Get(oapi, "/health", func(c fiber.Ctx, in struct{}) (versionInfo, error) {
+		return versionInfo{Version: VersionFromCtx(c)}, nil
+	}, OpenAPIOptions{OperationID: "health"})
+	Delete(oapi, "/users/:id", func(c fiber.Ctx, in versionedUserInput) (versionInfo, error) {
+		return versionInfo{Version: VersionFromCtx(c)}, nil
+	}, OpenAPIOptions{OperationID: "deleteUser", Versions: VersionRange{From: "1", To: "2"}})
+}
+
+func TestVersioning_ByPath(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2", "3"}}})
+	registerVersionedRoutes(oapi)
+
+	resp := sendRequest(t, oapi, "GET", "/v1/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"name":"Ada Lovelace 1"}`, string(resp.body))
+
+	resp = sendRequest(t, oapi, "GET", "/v3/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"firstName":"Ada","lastName":"3"}`, string(resp.body))
+
+	resp = sendRequest(t, oapi, "DELETE", "/v3/users/42", "")
+	assert.Equal(t, 405, resp.StatusCode, "deleteUser ends at version 2")
+	resp = sendRequest(t, oapi, "DELETE", "/v2/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	resp = sendRequest(t, oapi, "GET", "/v4/users/42", "")
+	assert.Equal(t, 404, resp.StatusCode)
+	resp = sendRequest(t, oapi, "GET", "/users/42", "")
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestVersioning_ByHeader(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2", "3"}, Strategy: VersionByHeader, Default: "2"}})
+	registerVersionedRoutes(oapi)
+
+	resp := sendRequest(t, oapi, "GET", "/users/42", "", "Accept-Version", "1")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"name":"Ada Lovelace 1"}`, string(resp.body))
+
+	resp = sendRequest(t, oapi, "GET", "/users/42", "")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"firstName":"Ada","lastName":"2"}`, string(resp.body), "default version")
+
+	resp = sendRequest(t, oapi, "GET", "/health", "", "Accept-Version", "3")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"version":"3"}`, string(resp.body))
+
+	resp = sendRequest(t, oapi, "DELETE", "/users/42", "", "Accept-Version", "3")
+	assert.Equal(t, 404, resp.StatusCode, "operation absent from the requested version")
+
+	resp = sendRequest(t, oapi, "GET", "/users/42", "", "Accept-Version", "9")
+	require.Equal(t, 406, resp.StatusCode)
+	env := resp.envelope(t)
+	require.Len(t, env.Errors, 1)
+	assert.Equal(t, "unsupported_version", env.Errors[0].Type)
+	assert.Equal(t, []any{"header", "Accept-Version"}, env.Errors[0].Loc)
+	assert.Equal(t, `API version "9" is not supported; supported: 1, 2, 3`, env.Errors[0].Msg)
+	assert.Equal(t, "versions=1 2 3", env.Errors[0].Constraint)
+}
+
+func TestVersioning_ByMediaType(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2"}, Strategy: VersionByMediaType}})
+	registerVersionedRoutes(oapi)
+
+	resp := sendRequest(t, oapi, "GET", "/users/42", "", "Accept", "text/html, application/json; version=1")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"name":"Ada Lovelace 1"}`, string(resp.body))
+
+	resp = sendRequest(t, oapi, "GET", "/users/42", "", "Accept", "application/json")
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.JSONEq(t, `{"firstName":"Ada","lastName":"2"}`, string(resp.body), "latest by default")
+
+	resp = sendRequest(t, oapi, "GET", "/users/42", "", "Accept", "application/json; version=7")
+	assert.Equal(t, 406, resp.StatusCode)
+	assert.Contains(t, string(resp.body), `"loc":["header","Accept"]`)
+}
+
+func TestVersioning_NotFoundHandlerIgnoresOtherVersions(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2", "3"}, Strategy: VersionByHeader}})
+	registerVersionedRoutes(oapi)
+	oapi.UseNotFoundHandler()
+
+	resp := sendRequest(t, oapi, "DELETE", "/users/42", "", "Accept-Version", "3")
+	assert.Equal(t, 405, resp.StatusCode)
+	assert.Equal(t, "GET", resp.Header.Get("Allow"), "deleteUser is not part of version 3")
+
+	resp = sendRequest(t, oapi, "POST", "/users/42", "", "Accept-Version", "2")
+	assert.Equal(t, 405, resp.StatusCode)
+	assert.Equal(t, "GET, DELETE", resp.Header.Get("Allow"))
+
+	resp = sendRequest(t, oapi, "POST", "/users/42", "", "Accept-Version", "3")
+	assert.Equal(t, "GET", resp.Header.Get("Allow"))
+
+	resp = sendRequest(t, oapi, "GET", "/nowhere", "", "Accept-Version", "2")
+	assert.Equal(t, 404, resp.StatusCode)
+}
+
+func TestVersioning_SpecPerVersion(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2"}, Strategy: VersionByHeader}, OpenAPIVersion: "2024.10.1"})
+	registerVersionedRoutes(oapi)
+
+	v1 := oapi.ForVersion("1").GenerateOpenAPISpec()
+	assert.Equal(t, "2024.10.1", v1["info"].(map[string]interface{})["version"], "info.version is kept as configured")
+	assert.Equal(t, "1", v1["info"].(map[string]interface{})["x-api-version"])
+	getV1 := v1["paths"].(map[string]interface{})["/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	schemas := v1["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.Contains(t, schemas, "userV1")
+	assert.NotContains(t, schemas, "userV2")
+	assert.Contains(t, getV1["responses"], "406")
+
+	var versionParam map[string]interface{}
+	for _, p := range getV1["parameters"].([]map[string]interface{}) {
+		if p["name"] == "Accept-Version" {
+			versionParam = p
+		}
+	}
+	require.NotNil(t, versionParam)
+	assert.Equal(t, []string{"1"}, versionParam["schema"].(map[string]interface{})["enum"])
+
+	latest := oapi.GenerateOpenAPISpec()
+	assert.Equal(t, "2024.10.1", latest["info"].(map[string]interface{})["version"])
+	assert.Equal(t, "2", latest["info"].(map[string]interface{})["x-api-version"], "the default spec describes the default version")
+	assert.NotContains(t, latest["components"].(map[string]interface{})["schemas"], "userV1")
+	assert.Empty(t, oapi.LintSpec(), "duplicate operationIds across versions are fine")
+
+	resp := sendRequest(t, oapi, "GET", "/v1/openapi.json", "")
+	require.Equal(t, 200, resp.StatusCode)
+	assert.Contains(t, string(resp.body), "userV1")
+	assert.NotContains(t, string(resp.body), "userV2")
+	resp = sendRequest(t, oapi, "GET", "/v2/docs", "")
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestVersioning_PathSpecAndURLFor(t *testing.T) {
+	oapi := newTestApp(Config{Versioning: &Versioning{Versions: []string{"1", "2"}, PathPrefix: "/api/v"}})
+	registerVersionedRoutes(oapi)
+
+	paths := oapi.ForVersion("1").GenerateOpenAPISpec()["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/api/v1/users/{id}")
+	assert.NotContains(t, paths, "/api/v2/users/{id}")
+
+	got, err := oapi.URLFor("getUser", map[string]any{"id": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v2/users/1", got)
+	got, err = oapi.ForVersion("1").URLFor("getUser", map[string]any{"id": 1})
+	require.NoError(t, err)
+	assert.Equal(t, "/api/v1/users/1", got)
+}
+
+func TestVersioning_RegistrationErrors(t *testing.T) {
+	assert.Panics(t, func() {
+		registerVersionedRoutes(newTestApp(Config{Versioning: &Versioning{Versions: []string{"2", "3"}}}))
+	}, "range bound not in Versions")
+	assert.Panics(t, func() {
+		Get(newTestApp(), "/x", func(c fiber.Ctx, in struct{}) (struct{}, error) {
+			return struct{}{}, nil
+		}, OpenAPIOptions{Versions: VersionRange{From: "1"}})
+	}, "Versions without Config.Versioning")
+}